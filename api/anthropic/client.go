@@ -0,0 +1,228 @@
+package anthropic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl     = "https://api.anthropic.com"
+	defaultModel   = "claude-3-5-haiku-latest"
+	apiVersion     = "2023-06-01"
+	stopMaxTokens  = "max_tokens"
+	toolName       = "submit_translations"
+	overloadedCode = 529
+	// contextWindow Claude 模型的上下文窗口
+	contextWindow = 200000
+)
+
+type Config struct {
+	baseUrl       string
+	model         string
+	maxTokens     int
+	temperature   float64
+	ctxOffset     int
+	jsonMode      bool
+	blockTokens   int
+	prompt        *llm.Prompt
+	retry         int
+	retryWaitTime time.Duration
+	debug         bool
+}
+
+type Client struct {
+	cfg     *Config
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+	queue   *llm.Queue
+}
+
+func NewClient(token string, logger *zap.SugaredLogger, options ...func(*Config)) *Client {
+	cfg := &Config{
+		model:       defaultModel,
+		maxTokens:   4096,
+		temperature: 0.2,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	if cfg.baseUrl == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		cfg.baseUrl = defaultUrl
+	}
+	if cfg.ctxOffset == 0 {
+		cfg.ctxOffset = 10
+	}
+	if cfg.prompt == nil {
+		cfg.prompt, _ = llm.NewPrompt("", "", "")
+	}
+	httpClient := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.baseUrl, "/")).
+		SetHeader("x-api-key", token).
+		SetHeader("anthropic-version", apiVersion).
+		SetRetryCount(cfg.retry).
+		SetRetryWaitTime(cfg.retryWaitTime).
+		SetDebug(cfg.debug)
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable, overloadedCode:
+			return true
+		}
+		return false
+	})
+	return &Client{
+		cfg:     cfg,
+		httpCli: httpClient,
+		logger:  logger,
+		queue:   llm.NewQueue(cfg.ctxOffset),
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	system, err := c.cfg.prompt.System(sourceLang, targetLang, c.cfg.jsonMode)
+	if err != nil {
+		return nil, err
+	}
+	system = llm.AddExamples(ctx, system)
+	if c.cfg.jsonMode {
+		return llm.Structured(ctx, text, c.logger, func(ctx context.Context, text []string) ([]string, error) {
+			return c.translateJSON(ctx, system, text)
+		})
+	}
+	// Messages API 要求 user/assistant 严格交替，请求成功后才把本轮对话写入上下文
+	user := llm.Message{Role: llm.RoleUser, Content: llm.Join(text)}
+	result, err := c.send(ctx, MessagesRequest{
+		System:   system,
+		Messages: append(append([]llm.Message{}, c.queue.Get()...), user),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var content strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	if content.Len() == 0 {
+		c.logger.Infof("Translation result is empty")
+		return nil, nil
+	}
+	parsed, err := llm.ParseContent(content.String())
+	if err != nil || len(parsed) != len(text) {
+		// 条数不一致的回复会被拆分重译，不写入上下文
+		return parsed, err
+	}
+	c.queue.Enqueue(user)
+	c.queue.Enqueue(llm.Message{Role: llm.RoleAssistant, Content: content.String()})
+
+	return parsed, nil
+}
+
+// translateJSON 强制模型调用 submit_translations 工具，工具参数即以字幕编号为键的译文
+func (c *Client) translateJSON(ctx context.Context, system string, text []string) ([]string, error) {
+	user := llm.Message{Role: llm.RoleUser, Content: llm.EncodeCues(text)}
+	result, err := c.send(ctx, MessagesRequest{
+		System:   system,
+		Messages: append(append([]llm.Message{}, c.queue.Get()...), user),
+		Tools: []Tool{{
+			Name:        toolName,
+			Description: "Submit the final translation of every subtitle, keyed by subtitle id",
+			InputSchema: llm.CueSchema(len(text)),
+		}},
+		ToolChoice: &ToolChoice{Type: "tool", Name: toolName},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range result.Content {
+		if block.Type != "tool_use" || block.Name != toolName {
+			continue
+		}
+		translated, err := llm.DecodeCues(string(block.Input), len(text))
+		if err != nil {
+			return nil, err
+		}
+		c.queue.Enqueue(user)
+		c.queue.Enqueue(llm.Message{Role: llm.RoleAssistant, Content: string(block.Input)})
+		return translated, nil
+	}
+	return nil, fmt.Errorf("%w: no %s tool call in reply", llm.ErrMisaligned, toolName)
+}
+
+func (c *Client) send(ctx context.Context, body MessagesRequest) (*MessagesResponse, error) {
+	body.Model = c.cfg.model
+	body.MaxTokens = c.cfg.maxTokens
+	body.Temperature = c.cfg.temperature
+	var result MessagesResponse
+	var errResult ErrorResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&result).
+		SetError(&errResult).
+		Post("/v1/messages")
+	if err != nil {
+		c.logger.Errorw("Messages error", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, apiError(resp, errResult)
+	}
+	if result.StopReason == stopMaxTokens {
+		// 译文被截断，拆分后重新翻译
+		return nil, api.NewError(api.KindTooLong, stopMaxTokens, fmt.Sprintf("reply truncated at max_tokens %d", c.cfg.maxTokens))
+	}
+	return &result, nil
+}
+
+// apiError 按 HTTP 状态码和错误类型分类，见 https://docs.anthropic.com/en/api/errors
+func apiError(resp *resty.Response, e ErrorResponse) error {
+	err := api.HTTPError(resp.StatusCode(), resp.Header(), strings.TrimSpace(e.Error.Type+" "+e.Error.Message))
+	msg := strings.ToLower(e.Error.Message)
+	switch {
+	case e.Error.Type == "overloaded_error":
+		err.Kind = api.KindTransient
+	case strings.Contains(msg, "credit balance"):
+		err.Kind = api.KindQuota
+	case e.Error.Type == "invalid_request_error" && strings.Contains(msg, "too long"):
+		err.Kind = api.KindTooLong
+	}
+	return err
+}
+
+// Sequential 上下文队列依赖请求顺序，不能并发翻译
+func (c *Client) Sequential() bool {
+	return true
+}
+
+// UsesExamples 实现 llm.ExampleUser
+func (c *Client) UsesExamples() bool {
+	return true
+}
+
+// SetGlossary 实现 llm.GlossaryUser，术语表写入系统提示词
+func (c *Client) SetGlossary(terms []llm.Term) {
+	c.cfg.prompt.SetGlossary(terms)
+}
+
+// Limits 回复需要放进 max_tokens，字幕 token 预算按 llm.BlockTokens 推算；Claude 没有公开的分词器，token 数为估算
+func (c *Client) Limits() api.Limits {
+	tokens := c.cfg.blockTokens
+	if tokens <= 0 {
+		tokens = llm.BlockTokens(contextWindow, c.cfg.maxTokens)
+	}
+	return llm.Limits(tokens, nil)
+}
+
+func (c *Client) Close() error {
+	return nil
+}
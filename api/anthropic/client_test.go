@@ -0,0 +1,121 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 Messages API，译文为 "zh:" + 原文，并检查对话严格交替。
+// dropLast 为 true 时，多于一条的字幕会漏掉最后一条，用于触发拆分。
+func newTestServer(t *testing.T, stopReason string, dropLast bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+			return
+		}
+		var req MessagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		for i, m := range req.Messages {
+			want := llm.RoleUser
+			if i%2 == 1 {
+				want = llm.RoleAssistant
+			}
+			if m.Role != want {
+				t.Errorf("message %d role = %s, want %s", i, m.Role, want)
+			}
+		}
+		last := req.Messages[len(req.Messages)-1].Content
+		resp := MessagesResponse{Model: req.Model, StopReason: stopReason}
+		if req.ToolChoice != nil {
+			var cues map[string]string
+			if err := json.Unmarshal([]byte(last), &cues); err != nil {
+				t.Fatal(err)
+			}
+			if dropLast && len(cues) > 1 {
+				delete(cues, "2")
+			}
+			for id, text := range cues {
+				cues[id] = "zh:" + text
+			}
+			input, _ := json.Marshal(cues)
+			resp.StopReason = "tool_use"
+			resp.Content = append(resp.Content, ContentBlock{Type: "tool_use", Name: req.ToolChoice.Name, Input: input})
+		} else {
+			parts := llm.Split(last)
+			if dropLast && len(parts) > 1 {
+				parts = parts[:len(parts)-1]
+			}
+			for i, p := range parts {
+				parts[i] = "zh:" + p
+			}
+			resp.Content = append(resp.Content, ContentBlock{Type: "text", Text: "译文终稿:" + llm.Join(parts)})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name       string
+		token      string
+		stopReason string
+		jsonMode   bool
+		dropLast   bool
+		want       []string
+		wantErr    bool
+		wantKind   api.ErrorKind
+	}{
+		{name: "translate", token: "key", stopReason: "end_turn", want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "invalid key", token: "bad", wantErr: true, wantKind: api.KindAuth},
+		{name: "max tokens", token: "key", stopReason: "max_tokens", wantErr: true, wantKind: api.KindTooLong},
+		{name: "json", token: "key", jsonMode: true, want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "json split on missing id", token: "key", jsonMode: true, dropLast: true, want: []string{"zh:一行目", "zh:二行目"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.stopReason, tt.dropLast)
+			defer server.Close()
+			c := NewClient(tt.token, logger, WithBaseUrl(server.URL), WithCtxOffset(1), WithJSON(tt.jsonMode))
+			// 连续翻译两次，第二次请求带上第一轮上下文
+			for i := 0; i < 2; i++ {
+				got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if kind := api.KindOf(err); kind != tt.wantKind {
+					t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+				}
+				if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+					t.Errorf("Translate() got = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_Translate_misaligned(t *testing.T) {
+	server := newTestServer(t, "end_turn", true)
+	defer server.Close()
+	c := NewClient("key", zap.NewNop().Sugar(), WithBaseUrl(server.URL))
+	got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Translate() = %q, %v", got, err)
+	}
+	if c.queue.Size() != 0 {
+		t.Errorf("queue keeps %d messages of a misaligned reply", c.queue.Size())
+	}
+}
@@ -0,0 +1,76 @@
+package anthropic
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api/llm"
+)
+
+func WithBaseUrl(baseUrl string) func(*Config) {
+	return func(c *Config) {
+		c.baseUrl = baseUrl
+	}
+}
+
+func WithModel(model string) func(*Config) {
+	return func(c *Config) {
+		c.model = model
+	}
+}
+
+func WithMaxTokens(maxTokens int) func(*Config) {
+	return func(c *Config) {
+		c.maxTokens = maxTokens
+	}
+}
+
+func WithTemperature(t float64) func(*Config) {
+	return func(c *Config) {
+		c.temperature = t
+	}
+}
+
+func WithCtxOffset(l int) func(*Config) {
+	return func(c *Config) {
+		c.ctxOffset = l
+	}
+}
+
+// WithJSON 通过工具调用获取以字幕编号为键的结构化译文
+func WithJSON(jsonMode bool) func(*Config) {
+	return func(c *Config) {
+		c.jsonMode = jsonMode
+	}
+}
+
+func WithRetry(retry int) func(*Config) {
+	return func(c *Config) {
+		c.retry = retry
+	}
+}
+
+func WithRetryWaitTime(waitTime time.Duration) func(*Config) {
+	return func(c *Config) {
+		c.retryWaitTime = waitTime
+	}
+}
+
+func WithDebug(debug bool) func(*Config) {
+	return func(c *Config) {
+		c.debug = debug
+	}
+}
+
+// WithBlockTokens 一次请求的字幕 token 预算，为 0 时按模型推算
+func WithBlockTokens(tokens int) func(*Config) {
+	return func(c *Config) {
+		c.blockTokens = tokens
+	}
+}
+
+// WithPrompt 系统提示词模板，默认使用按语言对选择的内置模板
+func WithPrompt(prompt *llm.Prompt) func(*Config) {
+	return func(c *Config) {
+		c.prompt = prompt
+	}
+}
@@ -0,0 +1,43 @@
+package anthropic
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "anthropic",
+		Description: "Anthropic Messages API (Claude models)",
+		Settings: append([]api.Setting{
+			{Name: "api_key", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "API key"},
+			{Name: "model", Type: api.StringSetting, Default: defaultModel, Flag: "gptModel", Description: "Claude model"},
+			{Name: "max_tokens", Type: api.IntSetting, Default: "4096", Description: "Maximum tokens of one reply"},
+			{Name: "temperature", Type: api.FloatSetting, Default: "0.2", Description: "Sampling temperature"},
+			{Name: "ctx_offset", Type: api.IntSetting, Default: "3", Flag: "ctxOffset", Description: "Number of previous exchanges kept as context"},
+			{Name: "json", Type: api.BoolSetting, Default: "false", Description: "Request output keyed by cue ID through forced tool use"},
+		}, append(append(llm.PromptSettings(), llm.LimitSettings()...), api.HTTPSettings(defaultUrl)...)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			prompt, err := llm.PromptFromConfig(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return NewClient(cfg.String("api_key"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithModel(cfg.String("model")),
+				WithMaxTokens(cfg.Int("max_tokens")),
+				WithTemperature(cfg.Float("temperature")),
+				WithCtxOffset(cfg.Int("ctx_offset")),
+				WithJSON(cfg.Bool("json")),
+				WithBlockTokens(cfg.Int("block_tokens")),
+				WithPrompt(prompt),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+				WithDebug(cfg.Bool("debug")),
+			), nil
+		},
+	})
+}
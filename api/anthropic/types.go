@@ -0,0 +1,53 @@
+package anthropic
+
+import (
+	"encoding/json"
+
+	"github.com/AnTengye/srtt/api/llm"
+)
+
+// MessagesRequest POST /v1/messages
+type MessagesRequest struct {
+	Model       string        `json:"model"`
+	MaxTokens   int           `json:"max_tokens"`
+	System      string        `json:"system,omitempty"`
+	Messages    []llm.Message `json:"messages"`
+	Temperature float64       `json:"temperature"`
+	Tools       []Tool        `json:"tools,omitempty"`
+	ToolChoice  *ToolChoice   `json:"tool_choice,omitempty"`
+}
+
+// Tool 工具定义，JSON 模式下用于约束输出结构
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type ToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type MessagesResponse struct {
+	ID         string         `json:"id"`
+	Model      string         `json:"model"`
+	StopReason string         `json:"stop_reason"`
+	Content    []ContentBlock `json:"content"`
+}
+
+// ContentBlock 回复内容块，type 为 text 或 tool_use
+type ContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type ErrorResponse struct {
+	Type  string `json:"type"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
@@ -0,0 +1,181 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl = "https://api.cognitive.microsofttranslator.com"
+	apiVersion = "3.0"
+	// maxElements、maxChars 单次请求的数组长度和字符数上限
+	maxElements = 1000
+	maxChars    = 50000
+)
+
+// markupRe 字幕中的格式标签，html 模式下原样发送，其余文本需要转义
+var markupRe = regexp.MustCompile(`(?i)</?(?:i|b|u|s|font)(?:\s[^<>]*)?>`)
+
+type Client struct {
+	key     string
+	region  string
+	html    bool
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+}
+
+// NewClient region 为资源所在区域，使用全局资源时可以为空
+func NewClient(key string, region string, logger *zap.SugaredLogger, options ...func(*resty.Client)) *Client {
+	httpClient := resty.New()
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	httpClient.SetQueryParam("api-version", apiVersion)
+	httpClient.SetHeader("Ocp-Apim-Subscription-Key", key)
+	if region != "" {
+		httpClient.SetHeader("Ocp-Apim-Subscription-Region", region)
+	}
+	for _, option := range options {
+		option(httpClient)
+	}
+	if httpClient.BaseURL == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		httpClient.SetBaseURL(defaultUrl)
+	}
+	return &Client{
+		key:     key,
+		region:  region,
+		html:    httpClient.QueryParam.Get("textType") == "html",
+		httpCli: httpClient,
+		logger:  logger,
+	}
+}
+
+// Translate 字幕以 JSON 数组发送，每条字幕对应一个译文
+func (c Client) Translate(ctx context.Context, t []string, sourceLang string, targetLang string) ([]string, error) {
+	if len(t) > maxElements {
+		return nil, api.NewError(api.KindTooLong, "", fmt.Sprintf("%d texts exceed the limit of %d", len(t), maxElements))
+	}
+	body := make([]TextItem, len(t))
+	chars := 0
+	for i, text := range t {
+		if c.html {
+			text = escapeHTML(text)
+		}
+		body[i] = TextItem{Text: text}
+		chars += utf8.RuneCountInString(text)
+	}
+	if chars > maxChars {
+		c.logger.Errorw("Translation failed", zap.String("reason", "text too long"))
+		return nil, api.NewError(api.KindTooLong, "", fmt.Sprintf("%d characters, limit is %d", chars, maxChars))
+	}
+	req := c.httpCli.R().SetContext(ctx).SetQueryParam("to", lang(strings.ToLower(targetLang)))
+	if sourceLang != "" && !strings.EqualFold(sourceLang, "auto") {
+		req.SetQueryParam("from", lang(strings.ToLower(sourceLang)))
+	}
+	var result []TranslateResult
+	resp, err := req.SetBody(body).SetResult(&result).SetError(&ErrorResponse{}).Post("/translate")
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, c.apiError(resp)
+	}
+	if len(result) != len(t) {
+		return nil, fmt.Errorf("Translation failed: got %d translations for %d texts", len(result), len(t))
+	}
+	translatedText := make([]string, len(result))
+	for i, r := range result {
+		if len(r.Translations) > 0 {
+			translatedText[i] = r.Translations[0].Text
+		}
+		if c.html {
+			translatedText[i] = html.UnescapeString(translatedText[i])
+		}
+	}
+	return translatedText, nil
+}
+
+// escapeHTML 转义字幕中的 <、>、&，保留 <i>、<font> 等格式标签
+func escapeHTML(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range markupRe.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// Lookup 查询单个词语的词典释义
+func (c Client) Lookup(ctx context.Context, word string, sourceLang string, targetLang string) ([]DictionaryTranslation, error) {
+	var result []LookupResult
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{"from": lang(strings.ToLower(sourceLang)), "to": lang(strings.ToLower(targetLang))}).
+		SetBody([]TextItem{{Text: word}}).
+		SetResult(&result).
+		SetError(&ErrorResponse{}).
+		Post("/dictionary/lookup")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, c.apiError(resp)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0].Translations, nil
+}
+
+// apiError 按错误码分类，错误码的前三位为 HTTP 状态码
+func (c Client) apiError(resp *resty.Response) error {
+	if e, ok := resp.Error().(*ErrorResponse); ok && e.Error.Code != 0 {
+		c.logger.Errorw("Translation failed", zap.Int("code", e.Error.Code), zap.String("message", e.Error.Message))
+		err := api.HTTPError(e.Error.Code/1000, resp.Header(), e.Error.Message)
+		err.Code = strconv.Itoa(e.Error.Code)
+		if kind, ok := errorKinds[e.Error.Code]; ok {
+			err.Kind = kind
+		}
+		return err
+	}
+	c.logger.Errorw("Translation failed", zap.String("status", resp.Status()))
+	return api.HTTPError(resp.StatusCode(), resp.Header(), resp.Status())
+}
+
+// errorKinds 需要单独区分的错误码
+var errorKinds = map[int]api.ErrorKind{
+	400019: api.KindInvalidLanguage, // One of the target languages is not valid
+	400035: api.KindInvalidLanguage, // The source language is not valid
+	400036: api.KindInvalidLanguage, // The target language is not valid
+	400050: api.KindTooLong,         // The input text is too long
+	400077: api.KindTooLong,         // The maximum request size has been exceeded
+	403001: api.KindQuota,           // The free quota has been exceeded
+}
+
+// Limits 每条字幕是数组中的一项
+func (c *Client) Limits() api.Limits {
+	return api.Limits{MaxSegments: maxElements, MaxChars: maxChars}
+}
+
+func (c *Client) Close() error {
+	return nil
+}
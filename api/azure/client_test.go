@@ -0,0 +1,101 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 Translator v3，译文为 "<to>:" + 原文
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "key" || r.Header.Get("Ocp-Apim-Subscription-Region") != "eastasia" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":401000,"message":"The request is not authorized"}}`))
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("api-version") != "3.0" {
+			t.Errorf("api-version = %q", q.Get("api-version"))
+		}
+		var body []TextItem
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		switch r.URL.Path {
+		case "/translate":
+			if q.Get("textType") != "html" || q.Get("profanityAction") != "Marked" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			result := make([]map[string]interface{}, len(body))
+			for i, item := range body {
+				if strings.Contains(item.Text, " & ") || strings.Contains(item.Text, "<3") {
+					t.Errorf("text not escaped: %q", item.Text)
+				}
+				result[i] = map[string]interface{}{
+					"translations": []map[string]string{{"text": q.Get("to") + ":" + item.Text, "to": q.Get("to")}},
+				}
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		case "/dictionary/lookup":
+			_, _ = w.Write([]byte(`[{"normalizedSource":"fly","displaySource":"fly","translations":[{"normalizedTarget":"volar","displayTarget":"volar","posTag":"VERB","confidence":0.42}]}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name     string
+		key      string
+		text     []string
+		want     []string
+		wantErr  bool
+		wantKind api.ErrorKind
+	}{
+		{name: "array", key: "key", text: []string{"<i>一行目</i>\n二行目", "三行目"}, want: []string{"zh-Hans:<i>一行目</i>\n二行目", "zh-Hans:三行目"}},
+		{name: "escape html", key: "key", text: []string{"<i>A & B</i> <3"}, want: []string{"zh-Hans:<i>A & B</i> <3"}},
+		{name: "unauthorized", key: "bad", text: []string{"一行目"}, wantErr: true, wantKind: api.KindAuth},
+		{name: "too many texts", key: "key", text: make([]string, maxElements+1), wantErr: true, wantKind: api.KindTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+			c := NewClient(tt.key, "eastasia", logger, WithBaseUrl(server.URL), WithTextType("html"), WithProfanity("Marked", ""))
+			got, err := c.Translate(context.Background(), tt.text, "ja", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Lookup(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewClient("key", "eastasia", zap.NewNop().Sugar(), WithBaseUrl(server.URL))
+	got, err := c.Lookup(context.Background(), "fly", "en", "es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].DisplayTarget != "volar" || got[0].PosTag != "VERB" {
+		t.Errorf("Lookup() got = %+v", got)
+	}
+}
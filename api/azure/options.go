@@ -0,0 +1,60 @@
+package azure
+
+import (
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func WithRetry(retry int) func(*resty.Client) {
+	return func(c *resty.Client) {
+		c.SetRetryCount(retry)
+	}
+}
+
+// with retry timeout
+func WithRetryWaitTime(waitTime time.Duration) func(*resty.Client) {
+	return func(c *resty.Client) {
+		c.SetRetryWaitTime(waitTime)
+	}
+}
+
+func WithDebug(debug bool) func(*resty.Client) {
+	return func(c *resty.Client) {
+		c.SetDebug(debug)
+	}
+}
+
+func WithBaseUrl(baseUrl string) func(*resty.Client) {
+	return func(c *resty.Client) {
+		c.SetBaseURL(baseUrl)
+	}
+}
+
+// WithTextType plain 或 html，html 模式下标签不会被翻译
+func WithTextType(textType string) func(*resty.Client) {
+	return func(c *resty.Client) {
+		setQueryParam(c, "textType", textType)
+	}
+}
+
+// WithProfanity action 为 NoAction、Marked 或 Deleted，marker 为 Asterisk 或 Tag（仅 Marked 时有效）
+func WithProfanity(action string, marker string) func(*resty.Client) {
+	return func(c *resty.Client) {
+		setQueryParam(c, "profanityAction", action)
+		setQueryParam(c, "profanityMarker", marker)
+	}
+}
+
+// WithCategory Custom Translator 的模型 category
+func WithCategory(category string) func(*resty.Client) {
+	return func(c *resty.Client) {
+		setQueryParam(c, "category", category)
+	}
+}
+
+func setQueryParam(c *resty.Client, param, value string) {
+	if value != "" {
+		c.SetQueryParam(param, value)
+	}
+}
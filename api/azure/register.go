@@ -0,0 +1,34 @@
+package azure
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "azure",
+		Description: "Microsoft Azure AI Translator v3",
+		Settings: append([]api.Setting{
+			{Name: "key", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "Subscription key of the Translator resource"},
+			{Name: "region", Type: api.StringSetting, Flag: "azure-region", Description: "Resource region, empty for global resources"},
+			{Name: "text_type", Type: api.StringSetting, Default: "plain", Description: "plain or html (html keeps <i>, <b>, <u>, <s> and <font> tags untranslated)"},
+			{Name: "profanity_action", Type: api.StringSetting, Description: "NoAction, Marked or Deleted"},
+			{Name: "profanity_marker", Type: api.StringSetting, Description: "Asterisk or Tag, used with profanity_action=Marked"},
+			{Name: "category", Type: api.StringSetting, Description: "Custom Translator category"},
+		}, api.HTTPSettings(defaultUrl)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(cfg.String("key"), cfg.String("region"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+				WithTextType(cfg.String("text_type")),
+				WithProfanity(cfg.String("profanity_action"), cfg.String("profanity_marker")),
+				WithCategory(cfg.String("category")),
+			), nil
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package azure
+
+// TextItem 请求体数组中的一项
+type TextItem struct {
+	Text string `json:"Text"`
+}
+
+type TranslateResult struct {
+	DetectedLanguage *struct {
+		Language string  `json:"language"`
+		Score    float64 `json:"score"`
+	} `json:"detectedLanguage,omitempty"`
+	Translations []struct {
+		Text string `json:"text"`
+		To   string `json:"to"`
+	} `json:"translations"`
+}
+
+type LookupResult struct {
+	NormalizedSource string                  `json:"normalizedSource"`
+	DisplaySource    string                  `json:"displaySource"`
+	Translations     []DictionaryTranslation `json:"translations"`
+}
+
+// DictionaryTranslation 词典查询的一个候选译法
+type DictionaryTranslation struct {
+	NormalizedTarget string  `json:"normalizedTarget"`
+	DisplayTarget    string  `json:"displayTarget"`
+	PosTag           string  `json:"posTag"`
+	Confidence       float64 `json:"confidence"`
+	BackTranslations []struct {
+		DisplayText string `json:"displayText"`
+	} `json:"backTranslations"`
+}
+
+type ErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// langMap 与 Azure 语言代码不一致的常用代码，其余原样传递
+var langMap = map[string]string{
+	"zh":    "zh-Hans",
+	"zh-cn": "zh-Hans",
+	"zh-tw": "zh-Hant",
+	"zh-hk": "zh-Hant",
+	"jp":    "ja",
+	"kor":   "ko",
+	"fra":   "fr",
+	"spa":   "es",
+	"ara":   "ar",
+	"no":    "nb",
+}
+
+func lang(code string) string {
+	if v, ok := langMap[code]; ok {
+		return v
+	}
+	return code
+}
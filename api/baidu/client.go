@@ -1,10 +1,13 @@
 package baidu
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/AnTengye/srtt/api"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 )
@@ -12,6 +15,10 @@ import (
 const (
 	defaultUrl = "https://fanyi-api.baidu.com/api/trans/vip/translate"
 	salt       = "aty123456"
+	// maxBytes 单次请求 query 的长度上限
+	maxBytes = 6000
+	// separator 多条字幕合并为一个 query 时的分隔符
+	separator = "\n----\n"
 )
 
 type Client struct {
@@ -45,20 +52,27 @@ func NewClient(apiKey string, secretKey string, logger *zap.SugaredLogger, optio
 	}
 }
 func before(text []string) string {
-	return strings.Join(text, "\n----\n")
+	return strings.Join(text, separator)
 }
-func (c Client) Translate(t []string, sourceLang string, targetLang string) ([]string, error) {
+
+// Limits 合并后的 query 不超过 maxBytes 字节
+func (c *Client) Limits() api.Limits {
+	return api.Limits{MaxBytes: maxBytes, Separator: separator}
+}
+
+func (c Client) Translate(ctx context.Context, t []string, sourceLang string, targetLang string) ([]string, error) {
 	text := before(t)
 	// text需要控制在6000bytes以内
-	if len(text) > 6000 {
+	if len(text) > maxBytes {
 		c.logger.Errorw("Translation failed", zap.String("reason", "text too long"))
-		return nil, fmt.Errorf("Translation failed: text too long")
+		return nil, api.NewError(api.KindTooLong, "", fmt.Sprintf("%d bytes, limit is %d", len(text), maxBytes))
 	}
 	sourceLang = langMap[strings.ToLower(sourceLang)]
 	targetLang = langMap[strings.ToLower(targetLang)]
 	var result BaiduResponse
 	s := sign(c.apiKey, text, salt, c.secret)
 	resp, err := c.httpCli.R().
+		SetContext(ctx).
 		SetFormData(map[string]string{"q": text, "from": sourceLang, "to": targetLang, "appid": c.apiKey, "salt": salt, "sign": s}).
 		SetResult(&result).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
@@ -69,22 +83,33 @@ func (c Client) Translate(t []string, sourceLang string, targetLang string) ([]s
 	}
 	if resp.IsError() {
 		c.logger.Errorw("Translation failed", zap.String("status", resp.Status()))
-		return nil, fmt.Errorf("Translation failed: %s", resp.Status())
+		return nil, api.HTTPError(resp.StatusCode(), resp.Header(), resp.Status())
 	}
 	if result.Error_code != "" && result.Error_code != "52000" {
-		c.logger.Errorw("Translation failed", zap.String("error_code", result.Error_code))
-		return nil, fmt.Errorf("Translation failed: %s", result.Error_code)
+		c.logger.Errorw("Translation failed", zap.String("error_code", result.Error_code), zap.String("error_msg", result.Error_msg))
+		e := api.NewError(errorKinds[result.Error_code], result.Error_code, result.Error_msg)
+		if e.Kind == api.KindRateLimited {
+			// 标准版 QPS 为 1，等待一秒即可
+			e.RetryAfter = time.Second
+		}
+		return nil, e
 	}
 	if len(result.TransResult) == 0 {
 		c.logger.Infof("Translation result is empty")
 		return nil, nil
 	}
-	translatedText := make([]string, 0, len(result.TransResult))
+	// 百度按行返回结果，多行字幕需要按分隔符重新合并
+	translatedText := make([]string, 0, len(t))
+	var lines []string
 	for _, v := range result.TransResult {
-		if v.Dst != "----" {
-			translatedText = append(translatedText, v.Dst)
+		if v.Src == "----" || v.Dst == "----" {
+			translatedText = append(translatedText, strings.Join(lines, "\n"))
+			lines = lines[:0]
+			continue
 		}
+		lines = append(lines, v.Dst)
 	}
+	translatedText = append(translatedText, strings.Join(lines, "\n"))
 	return translatedText, nil
 }
 
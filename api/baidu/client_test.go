@@ -1,64 +1,134 @@
 package baidu
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/AnTengye/srtt/api"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 )
 
+// newTestServer 模拟百度翻译接口：按行返回结果，译文为 "zh:" + 原文
+func newTestServer(t *testing.T, errorCode string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		q := r.PostForm.Get("q")
+		if r.PostForm.Get("sign") != sign(r.PostForm.Get("appid"), q, r.PostForm.Get("salt"), "secret") {
+			t.Errorf("invalid sign")
+		}
+		resp := BaiduResponse{From: r.PostForm.Get("from"), To: r.PostForm.Get("to"), Error_code: errorCode}
+		for _, line := range strings.Split(q, "\n") {
+			dst := line
+			if line != "----" {
+				dst = "zh:" + line
+			}
+			resp.TransResult = append(resp.TransResult, struct {
+				Src string `json:"src"`
+				Dst string `json:"dst"`
+			}{Src: line, Dst: dst})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
 func TestClient_Translate(t *testing.T) {
-	client := resty.New().SetBaseURL(defaultUrl)
 	logger := zap.NewNop().Sugar()
 	type fields struct {
-		apiKey  string
-		secret  string
-		httpCli *resty.Client
-		logger  *zap.SugaredLogger
+		apiKey    string
+		secret    string
+		errorCode string
 	}
 	type args struct {
-		text       string
+		text       []string
 		sourceLang string
 		targetLang string
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name     string
+		fields   fields
+		args     args
+		want     []string
+		wantErr  bool
+		wantKind api.ErrorKind
 	}{
 		{
-			name: "",
+			name: "multi line cue",
 			fields: fields{
-				apiKey:  "",
-				secret:  "",
-				httpCli: client,
-				logger:  logger,
+				apiKey: "appid",
+				secret: "secret",
+			},
+			args: args{
+				text:       []string{"何恥ずかしがってんだ、白って言え。", "一行目\n二行目"},
+				sourceLang: "ja",
+				targetLang: "zh",
+			},
+			want: []string{"zh:何恥ずかしがってんだ、白って言え。", "zh:一行目\nzh:二行目"},
+		},
+		{
+			name: "error code",
+			fields: fields{
+				apiKey:    "appid",
+				secret:    "secret",
+				errorCode: "54001",
 			},
 			args: args{
-				text:       "何恥ずかしがってんだ、白って言え。",
+				text:       []string{"こんにちは"},
 				sourceLang: "ja",
 				targetLang: "zh",
 			},
-			wantErr: false,
+			wantErr:  true,
+			wantKind: api.KindAuth,
+		},
+		{
+			name:     "qps limit",
+			fields:   fields{apiKey: "appid", secret: "secret", errorCode: "54003"},
+			args:     args{text: []string{"こんにちは"}, sourceLang: "ja", targetLang: "zh"},
+			wantErr:  true,
+			wantKind: api.KindRateLimited,
+		},
+		{
+			name:     "client ip",
+			fields:   fields{apiKey: "appid", secret: "secret", errorCode: "58000"},
+			args:     args{text: []string{"こんにちは"}, sourceLang: "ja", targetLang: "zh"},
+			wantErr:  true,
+			wantKind: api.KindAuth,
+		},
+		{
+			name:     "text too long",
+			fields:   fields{apiKey: "appid", secret: "secret"},
+			args:     args{text: []string{strings.Repeat("あ", 2001)}, sourceLang: "ja", targetLang: "zh"},
+			wantErr:  true,
+			wantKind: api.KindTooLong,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.fields.errorCode)
+			defer server.Close()
 			c := Client{
 				apiKey:  tt.fields.apiKey,
 				secret:  tt.fields.secret,
-				httpCli: tt.fields.httpCli,
-				logger:  tt.fields.logger,
+				httpCli: resty.New().SetBaseURL(server.URL),
+				logger:  logger,
 			}
-			got, err := c.Translate(tt.args.text, tt.args.sourceLang, tt.args.targetLang)
+			got, err := c.Translate(context.Background(), tt.args.text, tt.args.sourceLang, tt.args.targetLang)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got == "" {
-				t.Errorf("Translate() got = %v, want not %v", got, tt.want)
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
 			}
 		})
 	}
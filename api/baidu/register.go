@@ -0,0 +1,27 @@
+package baidu
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "baidu",
+		Description: "Baidu Translate open platform (fanyi-api.baidu.com)",
+		Settings: append([]api.Setting{
+			{Name: "app_id", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "APPID from the Baidu console"},
+			{Name: "secret", Type: api.StringSetting, Required: true, Flag: "apiSecret", Description: "Secret key from the Baidu console"},
+		}, api.HTTPSettings(defaultUrl)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(cfg.String("app_id"), cfg.String("secret"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+			), nil
+		},
+	})
+}
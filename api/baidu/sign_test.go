@@ -0,0 +1,31 @@
+package baidu
+
+import "testing"
+
+// Test_sign 使用百度翻译开放平台接入文档中的示例
+func Test_sign(t *testing.T) {
+	type args struct {
+		appid  string
+		q      string
+		salt   string
+		secret string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "document example",
+			args: args{appid: "2015063000000001", q: "apple", salt: "1435660288", secret: "12345678"},
+			want: "f89f9594663708c1605f3d736d01d2d4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sign(tt.args.appid, tt.args.q, tt.args.salt, tt.args.secret); got != tt.want {
+				t.Errorf("sign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
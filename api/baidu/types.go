@@ -1,5 +1,7 @@
 package baidu
 
+import "github.com/AnTengye/srtt/api"
+
 // 输入参数
 // 请求方式： 可使用 GET 或 POST 方式，如使用 POST 方式，Content-Type 请指定为：application/x-www-form-urlencoded
 // 字符编码：统一采用 UTF-8 编码格式
@@ -41,6 +43,25 @@ type BaiduResponse struct {
 		Dst string `json:"dst"`
 	} `json:"trans_result"`
 	Error_code string `json:"error_code"`
+	Error_msg  string `json:"error_msg"`
+}
+
+// errorKinds 百度错误码对应的错误类型
+// 52001 请求超时	52002 系统错误	52003 未授权用户	54001 签名错误
+// 54003 访问频率受限	54004 账户余额不足	54005 长query请求频繁	58000 客户端IP非法
+// 58001 译文语言方向不支持	58002 服务当前已关闭	90107 认证未通过或未生效
+var errorKinds = map[string]api.ErrorKind{
+	"52001": api.KindTransient,
+	"52002": api.KindTransient,
+	"52003": api.KindAuth,
+	"54001": api.KindAuth,
+	"54003": api.KindRateLimited,
+	"54004": api.KindQuota,
+	"54005": api.KindRateLimited,
+	"58000": api.KindAuth,
+	"58001": api.KindInvalidLanguage,
+	"58002": api.KindAuth,
+	"90107": api.KindAuth,
 }
 
 // 自动检测	auto	中文	zh	英语	en
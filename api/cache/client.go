@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// Client 为任意翻译引擎增加持久化缓存。
+// 整块非空字幕都命中时直接返回缓存；否则仍把整块发送给引擎以保留上下文，并缓存对齐的译文。
+type Client struct {
+	inner  api.TranslateApi
+	store  *Store
+	engine string
+	model  string
+	logger *zap.SugaredLogger
+}
+
+func NewClient(inner api.TranslateApi, store *Store, engine string, model string, logger *zap.SugaredLogger) *Client {
+	return &Client{
+		inner:  inner,
+		store:  store,
+		engine: engine,
+		model:  model,
+		logger: logger,
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	keys := make([][]byte, len(text))
+	cached := make([]string, len(text))
+	hit := true
+	for i, t := range text {
+		keys[i] = Key(c.model, sourceLang, targetLang, t)
+		// 空字幕（包括 ASS 矢量绘图）没有译文，不参与命中判断
+		if !hit || strings.TrimSpace(t) == "" {
+			continue
+		}
+		cached[i], hit = c.store.Get(c.engine, keys[i])
+	}
+	if hit {
+		c.logger.Debugf("Cache hit: %d lines", len(text))
+		return cached, nil
+	}
+	result, err := c.inner.Translate(ctx, text, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != len(text) {
+		c.logger.Debugf("Skip caching misaligned result: %d lines for %d", len(result), len(text))
+		return result, nil
+	}
+	values := make(map[string]string, len(result))
+	for i, r := range result {
+		if r != "" {
+			values[string(keys[i])] = r
+		}
+	}
+	if err := c.store.Put(c.engine, values); err != nil {
+		c.logger.Warnw("Cache write failed", zap.Error(err))
+	}
+	return result, nil
+}
+
+func (c *Client) Sequential() bool {
+	return api.IsSequential(c.inner)
+}
+
+func (c *Client) Limits() api.Limits {
+	return api.LimitsOf(c.inner)
+}
+
+func (c *Client) Close() error {
+	return c.inner.Close()
+}
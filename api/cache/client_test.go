@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fakeEngine struct {
+	calls int
+}
+
+func (f *fakeEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	f.calls++
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = strings.ToUpper(t)
+	}
+	return result, nil
+}
+
+func (f *fakeEngine) Close() error {
+	return nil
+}
+
+func TestClient_Translate(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	inner := &fakeEngine{}
+	c := NewClient(inner, store, "fake", "", zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		text      []string
+		want      []string
+		wantCalls int
+	}{
+		{name: "miss", text: []string{"a", "b"}, want: []string{"A", "B"}, wantCalls: 1},
+		{name: "hit with normalized text", text: []string{" a ", "b"}, want: []string{"A", "B"}, wantCalls: 1},
+		{name: "partial hit", text: []string{"b", "c"}, want: []string{"B", "C"}, wantCalls: 2},
+		{name: "hit", text: []string{"c", "a"}, want: []string{"C", "A"}, wantCalls: 2},
+		{name: "empty cue miss", text: []string{"d", ""}, want: []string{"D", ""}, wantCalls: 3},
+		{name: "empty cue hit", text: []string{"", "d", " "}, want: []string{"", "D", ""}, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Translate(context.Background(), tt.text, "ja", "zh")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+			if inner.calls != tt.wantCalls {
+				t.Errorf("engine calls = %d, want %d", inner.calls, tt.wantCalls)
+			}
+		})
+	}
+	if _, hit := store.Get("fake", Key("", "ja", "en", "a")); hit {
+		t.Errorf("cache hit for another target language")
+	}
+
+	st, err := store.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Total != 4 || st.Engines["fake"] != 4 {
+		t.Errorf("Stats() = %+v", st)
+	}
+	if n, err := store.Prune(time.Hour); err != nil || n != 0 {
+		t.Errorf("Prune() = %d, %v", n, err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := store.Stats(); st.Total != 0 {
+		t.Errorf("Stats() after Clear = %+v", st)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if !bytes.Equal(Key("", "ja", "zh", " a  b\t\r\nc "), Key("", "ja", "zh", "a b\nc")) {
+		t.Error("Key() should ignore horizontal and surrounding whitespace")
+	}
+	if bytes.Equal(Key("", "ja", "zh", "a\nb"), Key("", "ja", "zh", "a b")) {
+		t.Error("Key() should keep line breaks")
+	}
+}
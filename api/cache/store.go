@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const fileName = "cache.db"
+
+// entry 缓存的一条译文
+type entry struct {
+	Text    string `json:"text"`
+	Created int64  `json:"created"`
+}
+
+// Store 基于 bbolt 的译文缓存，每个翻译引擎一个 bucket
+type Store struct {
+	db   *bolt.DB
+	path string
+}
+
+// Stats 缓存统计
+type Stats struct {
+	Path    string
+	Size    int64
+	Engines map[string]int
+	Total   int
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// DefaultDir 默认缓存目录
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ".srtt-cache"
+	}
+	return filepath.Join(dir, "srtt")
+}
+
+// Open 打开 dir 下的缓存数据库，不存在时创建
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fileName)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db, path: path}, nil
+}
+
+// Key 根据模型、语言和规范化后的文本生成缓存 key，引擎由 bucket 区分
+func Key(model, sourceLang, targetLang, text string) []byte {
+	sum := sha256.Sum256([]byte(strings.Join([]string{model, strings.ToLower(sourceLang), strings.ToLower(targetLang), normalize(text)}, "\x00")))
+	return []byte(hex.EncodeToString(sum[:]))
+}
+
+// normalize 去掉首尾空白并合并行内的连续空白，保留换行，多行字幕与单行字幕使用不同的 key
+func normalize(text string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Get 查询缓存
+func (s *Store) Get(engine string, key []byte) (string, bool) {
+	var e entry
+	found := false
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(engine))
+		if b == nil {
+			return nil
+		}
+		v := b.Get(key)
+		if v == nil {
+			return nil
+		}
+		found = json.Unmarshal(v, &e) == nil
+		return nil
+	})
+	return e.Text, found
+}
+
+// Put 写入多条缓存
+func (s *Store) Put(engine string, values map[string]string) error {
+	now := time.Now().Unix()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(engine))
+		if err != nil {
+			return err
+		}
+		for k, text := range values {
+			v, err := json.Marshal(entry{Text: text, Created: now})
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stats 统计各引擎的缓存条数
+func (s *Store) Stats() (Stats, error) {
+	st := Stats{Path: s.path, Engines: map[string]int{}}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		st.Size = tx.Size()
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return b.ForEach(func(k, v []byte) error {
+				var e entry
+				if json.Unmarshal(v, &e) != nil {
+					return nil
+				}
+				created := time.Unix(e.Created, 0)
+				if st.Oldest.IsZero() || created.Before(st.Oldest) {
+					st.Oldest = created
+				}
+				if created.After(st.Newest) {
+					st.Newest = created
+				}
+				st.Engines[string(name)]++
+				st.Total++
+				return nil
+			})
+		})
+	})
+	return st, err
+}
+
+// Prune 删除早于 olderThan 的缓存，返回删除的条数
+func (s *Store) Prune(olderThan time.Duration) (int, error) {
+	deadline := time.Now().Add(-olderThan).Unix()
+	removed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			var keys [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				var e entry
+				if json.Unmarshal(v, &e) != nil || e.Created < deadline {
+					keys = append(keys, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			removed += len(keys)
+			return nil
+		})
+	})
+	return removed, err
+}
+
+// Clear 清空所有缓存
+func (s *Store) Clear() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var names [][]byte
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,144 @@
+package chatgpt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/sashabaranov/go-openai"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 chat completions 接口。JSON 模式下按字幕编号返回 "zh:" + 原文，
+// 第一次请求故意漏掉一条字幕以触发重试。
+func newTestServer(t *testing.T) *httptest.Server {
+	var calls int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Messages       []openai.ChatCompletionMessage `json:"messages"`
+			ResponseFormat *struct {
+				Type       openai.ChatCompletionResponseFormatType `json:"type"`
+				JSONSchema struct {
+					Strict bool `json:"strict"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		last := req.Messages[len(req.Messages)-1].Content
+		content := "译文终稿:zh:" + strings.ReplaceAll(last, "\n----\n", "\n----\nzh:")
+		if req.ResponseFormat != nil {
+			if req.ResponseFormat.Type != openai.ChatCompletionResponseFormatTypeJSONSchema || !req.ResponseFormat.JSONSchema.Strict {
+				t.Errorf("response_format = %s", req.ResponseFormat.Type)
+			}
+			var cues map[string]string
+			if err := json.Unmarshal([]byte(last), &cues); err != nil {
+				t.Fatal(err)
+			}
+			for id, text := range cues {
+				cues[id] = "zh:" + text
+			}
+			if calls == 1 {
+				delete(cues, "1")
+			}
+			b, _ := json.Marshal(cues)
+			content = string(b)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: content}},
+		}})
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name     string
+		jsonMode bool
+		want     []string
+	}{
+		{name: "separator", want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "json retry", jsonMode: true, want: []string{"zh:一行目", "zh:二行目"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+			c := NewClient("key", logger, WithBaseUrl(server.URL+"/v1"), WithJSON(tt.jsonMode))
+			got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want api.ErrorKind
+	}{
+		{name: "rate limited", err: &openai.APIError{HTTPStatusCode: 429, Code: "rate_limit_exceeded"}, want: api.KindRateLimited},
+		{name: "quota", err: &openai.APIError{HTTPStatusCode: 429, Code: "insufficient_quota"}, want: api.KindQuota},
+		{name: "context length", err: &openai.APIError{HTTPStatusCode: 400, Code: "context_length_exceeded"}, want: api.KindTooLong},
+		{name: "auth", err: &openai.APIError{HTTPStatusCode: 401, Code: "invalid_api_key"}, want: api.KindAuth},
+		{name: "server", err: &openai.RequestError{HTTPStatusCode: 502}, want: api.KindTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := api.KindOf(classify(tt.err)); got != tt.want {
+				t.Errorf("classify() kind = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Translate_truncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "译文终稿:zh:一行"},
+			FinishReason: openai.FinishReasonLength,
+		}}})
+	}))
+	defer server.Close()
+	for _, jsonMode := range []bool{false, true} {
+		c := NewClient("key", zap.NewNop().Sugar(), WithBaseUrl(server.URL+"/v1"), WithJSON(jsonMode))
+		_, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+		if api.KindOf(err) != api.KindTooLong {
+			t.Errorf("json %v: Translate() error = %v, want too long", jsonMode, err)
+		}
+		if n := len(c.queue.Get()); n != 0 {
+			t.Errorf("json %v: %d messages queued after truncation", jsonMode, n)
+		}
+	}
+}
+
+func TestClient_Translate_misaligned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "译文终稿:zh:一行目"}},
+		}})
+	}))
+	defer server.Close()
+	c := NewClient("key", zap.NewNop().Sugar(), WithBaseUrl(server.URL+"/v1"))
+	got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Translate() = %q, %v", got, err)
+	}
+	if n := len(c.queue.Get()); n != 0 {
+		t.Errorf("queue keeps %d messages of a misaligned reply", n)
+	}
+}
@@ -0,0 +1,42 @@
+package chatgpt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/sashabaranov/go-openai"
+)
+
+// classify 按 HTTP 状态码和 OpenAI 错误码分类
+func classify(err error) error {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		e := api.HTTPError(apiErr.HTTPStatusCode, nil, "")
+		e.Err = err
+		switch fmt.Sprint(apiErr.Code) {
+		case "insufficient_quota":
+			e.Kind = api.KindQuota
+		case "context_length_exceeded", "string_above_max_length":
+			e.Kind = api.KindTooLong
+		case "invalid_api_key":
+			e.Kind = api.KindAuth
+		}
+		return e
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		e := api.HTTPError(reqErr.HTTPStatusCode, nil, "")
+		e.Err = err
+		return e
+	}
+	return err
+}
+
+// truncated 回复达到输出上限被截断时返回 KindTooLong，由调用方拆分后重新翻译
+func truncated(resp openai.ChatCompletionResponse) error {
+	if resp.Choices[0].FinishReason == openai.FinishReasonLength {
+		return api.NewError(api.KindTooLong, string(openai.FinishReasonLength), "reply truncated at the output token limit")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package chatgpt
+
+import (
+	"strings"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"github.com/pkoukk/tiktoken-go"
+	"go.uber.org/zap"
+)
+
+// defaultModelLimits 未知模型按较小的上下文窗口和输出上限估算
+var defaultModelLimits = modelLimits{contextWindow: 8192, maxOutput: 4096}
+
+// modelLimits 模型的上下文窗口和输出 token 上限
+type modelLimits struct {
+	contextWindow int
+	maxOutput     int
+}
+
+// models 各模型的 token 上限，按模型名前缀匹配，更具体的前缀在前
+var models = []struct {
+	prefix string
+	modelLimits
+}{
+	{prefix: "gpt-4o", modelLimits: modelLimits{contextWindow: 128000, maxOutput: 16384}},
+	{prefix: "gpt-4.1", modelLimits: modelLimits{contextWindow: 1047576, maxOutput: 32768}},
+	{prefix: "gpt-4-turbo", modelLimits: modelLimits{contextWindow: 128000, maxOutput: 4096}},
+	{prefix: "gpt-4", modelLimits: modelLimits{contextWindow: 8192, maxOutput: 4096}},
+	{prefix: "gpt-3.5-turbo", modelLimits: modelLimits{contextWindow: 16385, maxOutput: 4096}},
+}
+
+// blockTokens 一次请求的字幕 token 预算，回复需要放进模型的输出上限
+func blockTokens(model string) int {
+	m := defaultModelLimits
+	for _, l := range models {
+		if strings.HasPrefix(model, l.prefix) {
+			m = l.modelLimits
+			break
+		}
+	}
+	return llm.BlockTokens(m.contextWindow, m.maxOutput)
+}
+
+// newTokenizer 按模型选择 tiktoken 编码，非 OpenAI 模型使用 cl100k_base；
+// 编码加载失败时退回 api.EstimateTokens 估算，估算值偏大，不会超出预算
+func newTokenizer(model string, logger *zap.SugaredLogger) api.Tokenizer {
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding(tiktoken.MODEL_CL100K_BASE)
+	}
+	if err != nil {
+		logger.Warnf("Failed to load tokenizer for %s, estimating tokens: %s", model, err)
+		return api.EstimateTokens
+	}
+	return func(text string) int {
+		return len(enc.EncodeOrdinary(text))
+	}
+}
+
+// Limits 按 token 预算装入字幕，token 数由模型对应的 tiktoken 编码计算
+func (c *Client) Limits() api.Limits {
+	c.tokenizerOnce.Do(func() {
+		c.tokenizer = newTokenizer(c.cfg.model, c.logger)
+	})
+	tokens := c.cfg.blockTokens
+	if tokens <= 0 {
+		tokens = blockTokens(c.cfg.model)
+	}
+	return llm.Limits(tokens, c.tokenizer)
+}
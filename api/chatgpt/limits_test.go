@@ -0,0 +1,59 @@
+package chatgpt
+
+import (
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"github.com/pkoukk/tiktoken-go"
+	"go.uber.org/zap"
+)
+
+func Test_blockTokens(t *testing.T) {
+	tests := map[string]int{
+		"gpt-4o-mini":   8192,
+		"gpt-4.1":       16384,
+		"gpt-4-0613":    2048,
+		"gpt-3.5-turbo": 2048,
+		"qwen2.5":       2048,
+	}
+	for model, want := range tests {
+		if got := blockTokens(model); got != want {
+			t.Errorf("blockTokens(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+// TestClient_Limits 内置的 cl100k_base、o200k_base 编码可用时按编码计数，而不是估算
+func TestClient_Limits(t *testing.T) {
+	text := "Previously on the show: 前回のあらすじ"
+	tests := []struct {
+		model    string
+		encoding string
+	}{
+		{model: "gpt-4", encoding: tiktoken.MODEL_CL100K_BASE},
+		{model: "gpt-4o-mini", encoding: tiktoken.MODEL_O200K_BASE},
+		{model: "qwen2.5", encoding: tiktoken.MODEL_CL100K_BASE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			c := NewClient("", zap.NewNop().Sugar(), WithModel(tt.model), WithBlockTokens(100))
+			limits := c.Limits()
+			if limits.MaxTokens != 100 || limits.Separator != llm.Separator {
+				t.Errorf("Limits() = %+v", limits)
+			}
+			enc, err := tiktoken.GetEncoding(tt.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := len(enc.EncodeOrdinary(text))
+			if got := limits.Tokenizer(text); got != want || got == api.EstimateTokens(text) {
+				t.Errorf("Tokenizer() = %d, want %d from %s (estimate %d)", got, want, tt.encoding, api.EstimateTokens(text))
+			}
+		})
+	}
+	c := NewClient("", zap.NewNop().Sugar(), WithModel("gpt-4"))
+	if got := c.Limits().Tokenizer("hello world"); got != 2 {
+		t.Errorf("cl100k_base tokens of %q = %d, want 2", "hello world", got)
+	}
+}
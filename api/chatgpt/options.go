@@ -1,5 +1,7 @@
 package chatgpt
 
+import "github.com/AnTengye/srtt/api/llm"
+
 func WithBaseUrl(baseUrl string) func(*Config) {
 	return func(c *Config) {
 		c.openaiCfg.BaseURL = baseUrl
@@ -20,3 +22,24 @@ func WithCtxOffset(l int) func(*Config) {
 		c.ctxOffset = l
 	}
 }
+
+// WithJSON 使用 JSON schema 结构化输出，按字幕编号校验译文
+func WithJSON(jsonMode bool) func(*Config) {
+	return func(c *Config) {
+		c.jsonMode = jsonMode
+	}
+}
+
+// WithBlockTokens 一次请求的字幕 token 预算，为 0 时按模型推算
+func WithBlockTokens(tokens int) func(*Config) {
+	return func(c *Config) {
+		c.blockTokens = tokens
+	}
+}
+
+// WithPrompt 系统提示词模板，默认使用按语言对选择的内置模板
+func WithPrompt(prompt *llm.Prompt) func(*Config) {
+	return func(c *Config) {
+		c.prompt = prompt
+	}
+}
@@ -0,0 +1,35 @@
+package chatgpt
+
+import (
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "chatgpt",
+		Description: "OpenAI compatible chat completion API",
+		Settings: append([]api.Setting{
+			{Name: "api_key", Type: api.StringSetting, Flag: "apiKey", Description: "API key"},
+			{Name: "url", Type: api.StringSetting, Default: defaultUrl, Flag: "apiUrl", Description: "API base url"},
+			{Name: "model", Type: api.StringSetting, Default: "gpt-3.5-turbo", Flag: "gptModel", Description: "Chat model"},
+			{Name: "ctx_offset", Type: api.IntSetting, Default: "3", Flag: "ctxOffset", Description: "Number of previous exchanges kept as context"},
+			{Name: "json", Type: api.BoolSetting, Default: "false", Description: "Request JSON output keyed by cue ID (response_format json_schema)"},
+		}, append(llm.PromptSettings(), llm.LimitSettings()...)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			prompt, err := llm.PromptFromConfig(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return NewClient(cfg.String("api_key"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithModel(cfg.String("model")),
+				WithCtxOffset(cfg.Int("ctx_offset")),
+				WithJSON(cfg.Bool("json")),
+				WithBlockTokens(cfg.Int("block_tokens")),
+				WithPrompt(prompt),
+			), nil
+		},
+	})
+}
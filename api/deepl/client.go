@@ -0,0 +1,163 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	freeUrl = "https://api-free.deepl.com"
+	proUrl  = "https://api.deepl.com"
+	// statusQuotaExceeded DeepL 字符额度用尽
+	statusQuotaExceeded = 456
+	// maxTexts、maxBytes 单次请求的 text 数量和请求体大小上限，字节数留出其他参数的余量
+	maxTexts = 50
+	maxBytes = 120 << 10
+)
+
+type Config struct {
+	baseUrl            string
+	formality          string
+	context            string
+	preserveFormatting bool
+	tagHandling        string
+	glossary           string
+	retry              int
+	retryWaitTime      time.Duration
+	debug              bool
+}
+
+type Client struct {
+	cfg     *Config
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+
+	mu         sync.Mutex
+	glossaries map[string]string
+}
+
+func NewClient(authKey string, logger *zap.SugaredLogger, options ...func(*Config)) *Client {
+	cfg := &Config{}
+	for _, option := range options {
+		option(cfg)
+	}
+	if cfg.baseUrl == "" {
+		cfg.baseUrl = proUrl
+		if strings.HasSuffix(authKey, ":fx") {
+			cfg.baseUrl = freeUrl
+		}
+		logger.Infof("No url provided, Using default url: %s", cfg.baseUrl)
+	}
+	httpClient := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.baseUrl, "/")).
+		SetHeader("Authorization", "DeepL-Auth-Key "+authKey).
+		SetRetryCount(cfg.retry).
+		SetRetryWaitTime(cfg.retryWaitTime).
+		SetDebug(cfg.debug)
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	return &Client{
+		cfg:        cfg,
+		httpCli:    httpClient,
+		logger:     logger,
+		glossaries: map[string]string{},
+	}
+}
+
+// Translate 每条字幕作为单独的 text 发送，译文与原文一一对应
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	if strings.EqualFold(sourceLang, "auto") {
+		sourceLang = ""
+	}
+	glossaryID, err := c.glossaryID(ctx, sourceLang, targetLang)
+	if err != nil {
+		c.logger.Errorw("Glossary failed", zap.Error(err))
+		return nil, err
+	}
+	var result TranslateResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(TranslateRequest{
+			Text:               text,
+			SourceLang:         strings.ToUpper(sourceLang),
+			TargetLang:         strings.ToUpper(targetLang),
+			Formality:          c.cfg.formality,
+			Context:            c.cfg.context,
+			PreserveFormatting: c.cfg.preserveFormatting,
+			TagHandling:        c.cfg.tagHandling,
+			GlossaryID:         glossaryID,
+		}).
+		SetResult(&result).
+		Post("/v2/translate")
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, c.apiError("Translation", resp)
+	}
+	if len(result.Translations) != len(text) {
+		return nil, fmt.Errorf("Translation failed: got %d translations for %d texts", len(result.Translations), len(text))
+	}
+	r := make([]string, len(result.Translations))
+	for i, t := range result.Translations {
+		r[i] = t.Text
+	}
+	return r, nil
+}
+
+// Usage 查询当前计费周期已用和可用的字符数
+func (c *Client) Usage(ctx context.Context) (Usage, error) {
+	var usage Usage
+	resp, err := c.httpCli.R().SetContext(ctx).SetResult(&usage).Get("/v2/usage")
+	if err != nil {
+		return usage, err
+	}
+	if resp.IsError() {
+		return usage, c.apiError("Usage", resp)
+	}
+	return usage, nil
+}
+
+func (c *Client) apiError(op string, resp *resty.Response) error {
+	var e ErrorResponse
+	_ = json.Unmarshal(resp.Body(), &e)
+	msg := strings.TrimSpace(e.Message + " " + e.Detail)
+	err := api.HTTPError(resp.StatusCode(), resp.Header(), msg)
+	err.Op = op
+	switch {
+	case resp.StatusCode() == http.StatusForbidden:
+		err.Message = strings.TrimSpace("invalid auth key " + msg)
+	case resp.StatusCode() == statusQuotaExceeded:
+		err.Kind = api.KindQuota
+		err.Message = strings.TrimSpace("character quota exceeded " + msg)
+	case resp.StatusCode() == http.StatusBadRequest && strings.Contains(strings.ToLower(msg), "_lang"):
+		// 如 "Value for 'target_lang' not supported."
+		err.Kind = api.KindInvalidLanguage
+	}
+	c.logger.Errorw(op+" failed", zap.String("status", resp.Status()), zap.String("message", msg))
+	return err
+}
+
+// Limits 每条字幕是一个 text 参数
+func (c *Client) Limits() api.Limits {
+	return api.Limits{MaxSegments: maxTexts, MaxBytes: maxBytes}
+}
+
+func (c *Client) Close() error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 DeepL v2 接口，译文为 "zh:" + 原文，命中术语表时加上 "[g]"
+func newTestServer(t *testing.T) (*httptest.Server, *[]Glossary) {
+	var glossaries []Glossary
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "DeepL-Auth-Key key:fx" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Wrong endpoint"}`))
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v2/translate":
+			var req TranslateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			if req.TargetLang != "ZH" || req.Formality != "prefer_less" {
+				t.Errorf("unexpected request: %+v", req)
+			}
+			var resp TranslateResponse
+			for _, text := range req.Text {
+				if req.GlossaryID != "" {
+					text = "[g]" + text
+				}
+				resp.Translations = append(resp.Translations, struct {
+					DetectedSourceLanguage string `json:"detected_source_language"`
+					Text                   string `json:"text"`
+				}{DetectedSourceLanguage: "JA", Text: "zh:" + text})
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "GET /v2/glossaries":
+			_ = json.NewEncoder(w).Encode(GlossaryList{Glossaries: glossaries})
+		case "POST /v2/glossaries":
+			var g Glossary
+			if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+				t.Fatal(err)
+			}
+			g.GlossaryID = "g1"
+			g.EntryCount = strings.Count(g.Entries, "\n")
+			g.Entries, g.EntriesFormat = "", ""
+			glossaries = append(glossaries, g)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(g)
+		case "GET /v2/usage":
+			_ = json.NewEncoder(w).Encode(Usage{CharacterCount: 100, CharacterLimit: 500000})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return server, &glossaries
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name     string
+		key      string
+		glossary string
+		want     []string
+		wantErr  bool
+		wantKind api.ErrorKind
+	}{
+		{name: "each cue separately", key: "key:fx", want: []string{"zh:一行目\n二行目", "zh:三行目"}},
+		{name: "glossary", key: "key:fx", glossary: "一行目\tfirst\n", want: []string{"zh:[g]一行目\n二行目", "zh:[g]三行目"}},
+		{name: "invalid key", key: "bad", wantErr: true, wantKind: api.KindAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, glossaries := newTestServer(t)
+			defer server.Close()
+			c := NewClient(tt.key, logger, WithBaseUrl(server.URL), WithFormality("prefer_less"), WithGlossary(tt.glossary))
+			for i := 0; i < 2; i++ {
+				got, err := c.Translate(context.Background(), []string{"一行目\n二行目", "三行目"}, "ja", "zh")
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if kind := api.KindOf(err); kind != tt.wantKind {
+					t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+				}
+				if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+					t.Errorf("Translate() got = %q, want %q", got, tt.want)
+				}
+			}
+			if tt.glossary != "" && len(*glossaries) != 1 {
+				t.Errorf("glossary created %d times, want 1", len(*glossaries))
+			}
+		})
+	}
+}
+
+func TestClient_Usage(t *testing.T) {
+	server, _ := newTestServer(t)
+	defer server.Close()
+	c := NewClient("key:fx", zap.NewNop().Sugar(), WithBaseUrl(server.URL))
+	got, err := c.Usage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CharacterCount != 100 || got.CharacterLimit != 500000 {
+		t.Errorf("Usage() got = %+v", got)
+	}
+}
+
+func Test_parseGlossary(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "comments and blanks", input: "# names\n\n白 \tShiro\r\n黒\tKuro\n", want: "白\tShiro\n黒\tKuro\n"},
+		{name: "missing target", input: "白\n", wantErr: true},
+		{name: "empty", input: "# nothing\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGlossary(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseGlossary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseGlossary() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
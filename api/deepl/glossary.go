@@ -0,0 +1,100 @@
+package deepl
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadGlossary 读取本地 TSV 术语表，每行 "原文\t译文"，忽略空行和 # 开头的注释
+func ReadGlossary(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return parseGlossary(f)
+}
+
+func parseGlossary(r io.Reader) (string, error) {
+	var b strings.Builder
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 || strings.TrimSpace(fields[0]) == "" || strings.TrimSpace(fields[1]) == "" {
+			return "", fmt.Errorf("glossary line %d: want \"source<TAB>target\"", n)
+		}
+		fmt.Fprintf(&b, "%s\t%s\n", strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1]))
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("glossary is empty")
+	}
+	return b.String(), nil
+}
+
+// glossaryLang 术语表只接受不带地区的小写语言代码，如 EN-US -> en
+func glossaryLang(lang string) string {
+	return strings.ToLower(strings.SplitN(lang, "-", 2)[0])
+}
+
+// glossaryID 按条目内容和语言对命名术语表，已存在同名术语表时直接复用
+func (c *Client) glossaryID(ctx context.Context, sourceLang, targetLang string) (string, error) {
+	if c.cfg.glossary == "" {
+		return "", nil
+	}
+	if sourceLang == "" {
+		return "", fmt.Errorf("deepl glossary requires a source language")
+	}
+	src, tgt := glossaryLang(sourceLang), glossaryLang(targetLang)
+	sum := sha256.Sum256([]byte(src + "\x00" + tgt + "\x00" + c.cfg.glossary))
+	name := "srtt-" + hex.EncodeToString(sum[:8])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := c.glossaries[name]; ok {
+		return id, nil
+	}
+	var list GlossaryList
+	resp, err := c.httpCli.R().SetContext(ctx).SetResult(&list).Get("/v2/glossaries")
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", c.apiError("List glossaries", resp)
+	}
+	for _, g := range list.Glossaries {
+		if g.Name == name && g.SourceLang == src && g.TargetLang == tgt {
+			c.glossaries[name] = g.GlossaryID
+			return g.GlossaryID, nil
+		}
+	}
+	var created Glossary
+	resp, err = c.httpCli.R().
+		SetContext(ctx).
+		SetBody(Glossary{Name: name, SourceLang: src, TargetLang: tgt, Entries: c.cfg.glossary, EntriesFormat: "tsv"}).
+		SetResult(&created).
+		Post("/v2/glossaries")
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", c.apiError("Create glossary", resp)
+	}
+	c.logger.Infof("Created DeepL glossary %s (%d entries)", name, created.EntryCount)
+	c.glossaries[name] = created.GlossaryID
+	return created.GlossaryID, nil
+}
@@ -0,0 +1,64 @@
+package deepl
+
+import (
+	"time"
+)
+
+// WithBaseUrl 默认根据 key 是否以 ":fx" 结尾选择 free 或 pro 域名
+func WithBaseUrl(baseUrl string) func(*Config) {
+	return func(c *Config) {
+		c.baseUrl = baseUrl
+	}
+}
+
+// WithFormality default, more, less, prefer_more, prefer_less
+func WithFormality(formality string) func(*Config) {
+	return func(c *Config) {
+		c.formality = formality
+	}
+}
+
+// WithContext 额外的背景说明，影响译文但本身不会被翻译和计费
+func WithContext(context string) func(*Config) {
+	return func(c *Config) {
+		c.context = context
+	}
+}
+
+func WithPreserveFormatting(preserve bool) func(*Config) {
+	return func(c *Config) {
+		c.preserveFormatting = preserve
+	}
+}
+
+// WithTagHandling xml 或 html，开启后 DeepL 会保留文本中的标签
+func WithTagHandling(tagHandling string) func(*Config) {
+	return func(c *Config) {
+		c.tagHandling = tagHandling
+	}
+}
+
+// WithGlossary 术语表条目（TSV 格式，见 ReadGlossary），首次翻译时在 DeepL 创建或复用
+func WithGlossary(entries string) func(*Config) {
+	return func(c *Config) {
+		c.glossary = entries
+	}
+}
+
+func WithRetry(retry int) func(*Config) {
+	return func(c *Config) {
+		c.retry = retry
+	}
+}
+
+func WithRetryWaitTime(waitTime time.Duration) func(*Config) {
+	return func(c *Config) {
+		c.retryWaitTime = waitTime
+	}
+}
+
+func WithDebug(debug bool) func(*Config) {
+	return func(c *Config) {
+		c.debug = debug
+	}
+}
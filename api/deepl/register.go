@@ -0,0 +1,43 @@
+package deepl
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "deepl",
+		Description: "Official DeepL API v2 (free keys ending in :fx use api-free.deepl.com)",
+		Settings: append([]api.Setting{
+			{Name: "api_key", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "DeepL auth key"},
+			{Name: "formality", Type: api.StringSetting, Description: "default, more, less, prefer_more or prefer_less"},
+			{Name: "context", Type: api.StringSetting, Description: "Extra context that influences the translation, e.g. a synopsis"},
+			{Name: "preserve_formatting", Type: api.BoolSetting, Default: "false", Description: "Keep punctuation and casing of the source"},
+			{Name: "tag_handling", Type: api.StringSetting, Description: "xml or html to keep markup tags"},
+			{Name: "glossary", Type: api.StringSetting, Description: "Local TSV glossary (source<TAB>target per line)"},
+		}, api.HTTPSettings("")...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			var glossary string
+			if path := cfg.String("glossary"); path != "" {
+				var err error
+				if glossary, err = ReadGlossary(path); err != nil {
+					return nil, err
+				}
+			}
+			return NewClient(cfg.String("api_key"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithFormality(cfg.String("formality")),
+				WithContext(cfg.String("context")),
+				WithPreserveFormatting(cfg.Bool("preserve_formatting")),
+				WithTagHandling(cfg.String("tag_handling")),
+				WithGlossary(glossary),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+				WithDebug(cfg.Bool("debug")),
+			), nil
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package deepl
+
+// TranslateRequest POST /v2/translate
+type TranslateRequest struct {
+	Text               []string `json:"text"`
+	SourceLang         string   `json:"source_lang,omitempty"`
+	TargetLang         string   `json:"target_lang"`
+	Formality          string   `json:"formality,omitempty"`
+	Context            string   `json:"context,omitempty"`
+	PreserveFormatting bool     `json:"preserve_formatting,omitempty"`
+	TagHandling        string   `json:"tag_handling,omitempty"`
+	GlossaryID         string   `json:"glossary_id,omitempty"`
+}
+
+type TranslateResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+// Usage GET /v2/usage
+type Usage struct {
+	CharacterCount int64 `json:"character_count"`
+	CharacterLimit int64 `json:"character_limit"`
+}
+
+// Glossary POST/GET /v2/glossaries
+type Glossary struct {
+	GlossaryID string `json:"glossary_id,omitempty"`
+	Name       string `json:"name"`
+	Ready      bool   `json:"ready,omitempty"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+	// Entries 与 EntriesFormat 只在创建时使用
+	Entries       string `json:"entries,omitempty"`
+	EntriesFormat string `json:"entries_format,omitempty"`
+	EntryCount    int    `json:"entry_count,omitempty"`
+}
+
+type GlossaryList struct {
+	Glossaries []Glossary `json:"glossaries"`
+}
+
+type ErrorResponse struct {
+	Message string `json:"message"`
+	Detail  string `json:"detail"`
+}
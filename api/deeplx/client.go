@@ -1,10 +1,11 @@
 package deeplx
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"strings"
 
+	"github.com/AnTengye/srtt/api"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 )
@@ -18,9 +19,10 @@ type Client struct {
 	logger  *zap.SugaredLogger
 }
 
-func (c Client) Translate(text []string, sourceLang string, targetLang string) ([]string, error) {
+func (c Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
 	var result DeeplxResponse
 	resp, err := c.httpCli.R().
+		SetContext(ctx).
 		SetBody(map[string]interface{}{"text": before(text), "source_lang": sourceLang, "target_lang": targetLang}).
 		SetResult(&result).
 		Post("")
@@ -30,7 +32,7 @@ func (c Client) Translate(text []string, sourceLang string, targetLang string) (
 	}
 	if resp.IsError() {
 		c.logger.Errorw("Translation failed", zap.String("status", resp.Status()))
-		return nil, fmt.Errorf("Translation failed: %s", resp.Status())
+		return nil, api.HTTPError(resp.StatusCode(), resp.Header(), resp.Status())
 	}
 	return after(result.Data), nil
 }
@@ -0,0 +1,24 @@
+package deeplx
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "deeplx",
+		Description: "DeepLX proxy (github.com/OwO-Network/DeepLX)",
+		Settings:    api.HTTPSettings(defaultUrl),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+			), nil
+		},
+	})
+}
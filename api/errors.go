@@ -0,0 +1,184 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorKind classifies engine errors so that the pipeline can decide whether to
+// back off, split the block, fall back to another engine or abort the run.
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	// KindAuth means the credentials, signature or client IP were rejected.
+	KindAuth
+	// KindQuota means the account has no characters or balance left.
+	KindQuota
+	// KindRateLimited means too many requests; retry after Error.RetryAfter.
+	KindRateLimited
+	// KindInvalidLanguage means the engine does not support the language pair.
+	KindInvalidLanguage
+	// KindTooLong means the request exceeds the engine's size limit.
+	KindTooLong
+	// KindTransient means a temporary server or network failure.
+	KindTransient
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindAuth:
+		return "authentication failed"
+	case KindQuota:
+		return "quota exhausted"
+	case KindRateLimited:
+		return "rate limited"
+	case KindInvalidLanguage:
+		return "unsupported language"
+	case KindTooLong:
+		return "text too long"
+	case KindTransient:
+		return "temporary failure"
+	}
+	return "error"
+}
+
+// Error is a classified engine error. Code is the provider error code or HTTP status.
+type Error struct {
+	Kind ErrorKind
+	// Op is the failed operation, "Translation" by default.
+	Op         string
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Sentinel errors for errors.Is, e.g. errors.Is(err, api.ErrRateLimited).
+var (
+	ErrAuth            = &Error{Kind: KindAuth}
+	ErrQuota           = &Error{Kind: KindQuota}
+	ErrRateLimited     = &Error{Kind: KindRateLimited}
+	ErrInvalidLanguage = &Error{Kind: KindInvalidLanguage}
+	ErrTooLong         = &Error{Kind: KindTooLong}
+	ErrTransient       = &Error{Kind: KindTransient}
+)
+
+// NewError returns an error of the given kind.
+func NewError(kind ErrorKind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	op := e.Op
+	if op == "" {
+		op = "Translation"
+	}
+	var b strings.Builder
+	b.WriteString(op)
+	b.WriteString(" failed: ")
+	b.WriteString(e.Kind.String())
+	if e.Code != "" {
+		b.WriteString(" (")
+		b.WriteString(e.Code)
+		b.WriteString(")")
+	}
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel errors by kind.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == "" && t.Message == "" && t.Err == nil && t.Kind == e.Kind
+}
+
+// KindOf returns the kind of err. Network errors that are not classified by the
+// engine are reported as transient.
+func KindOf(err error) ErrorKind {
+	if err == nil {
+		return KindUnknown
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return KindTransient
+	}
+	return KindUnknown
+}
+
+// RetryAfter returns how long the engine asked to wait, or 0 if it did not say.
+func RetryAfter(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// IsPermanent reports whether retrying on the same engine cannot succeed:
+// bad credentials, exhausted quota or an unsupported language pair.
+func IsPermanent(err error) bool {
+	switch KindOf(err) {
+	case KindAuth, KindQuota, KindInvalidLanguage:
+		return true
+	}
+	return false
+}
+
+// HTTPError classifies an HTTP error response by status code. Engines with
+// provider specific error codes should check those first.
+func HTTPError(status int, header http.Header, message string) *Error {
+	e := &Error{Code: strconv.Itoa(status), Message: message}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		e.Kind = KindAuth
+	case status == http.StatusPaymentRequired:
+		e.Kind = KindQuota
+	case status == http.StatusTooManyRequests:
+		e.Kind = KindRateLimited
+		if header != nil {
+			e.RetryAfter = ParseRetryAfter(header.Get("Retry-After"))
+		}
+	case status == http.StatusRequestEntityTooLarge || status == http.StatusRequestURITooLong:
+		e.Kind = KindTooLong
+	case status == http.StatusRequestTimeout || status >= 500:
+		e.Kind = KindTransient
+	}
+	return e
+}
+
+// ParseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func ParseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
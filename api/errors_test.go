@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHTTPError(t *testing.T) {
+	tests := []struct {
+		status         int
+		retryAfter     string
+		want           ErrorKind
+		wantRetryAfter time.Duration
+	}{
+		{status: http.StatusUnauthorized, want: KindAuth},
+		{status: http.StatusForbidden, want: KindAuth},
+		{status: http.StatusPaymentRequired, want: KindQuota},
+		{status: http.StatusTooManyRequests, retryAfter: "2", want: KindRateLimited, wantRetryAfter: 2 * time.Second},
+		{status: http.StatusRequestEntityTooLarge, want: KindTooLong},
+		{status: http.StatusBadGateway, want: KindTransient},
+		{status: http.StatusBadRequest, want: KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Retry-After", tt.retryAfter)
+			err := fmt.Errorf("engine: %w", HTTPError(tt.status, header, "message"))
+			if got := KindOf(err); got != tt.want {
+				t.Errorf("KindOf() = %v, want %v", got, tt.want)
+			}
+			if got := RetryAfter(err); got != tt.wantRetryAfter {
+				t.Errorf("RetryAfter() = %v, want %v", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := ParseRetryAfter("1.5"); got != 1500*time.Millisecond {
+		t.Errorf("ParseRetryAfter(seconds) = %v", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := ParseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("ParseRetryAfter(date) = %v", got)
+	}
+	if got := ParseRetryAfter("soon"); got != 0 {
+		t.Errorf("ParseRetryAfter(invalid) = %v", got)
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("baidu: %w", NewError(KindQuota, "54004", "insufficient balance"))
+	if !errors.Is(err, ErrQuota) || errors.Is(err, ErrAuth) {
+		t.Errorf("errors.Is() mismatched kind for %v", err)
+	}
+	if !IsPermanent(err) || IsPermanent(ErrRateLimited) {
+		t.Error("IsPermanent() should only hold for auth, quota and language errors")
+	}
+	if got, want := err.Error(), "baidu: Translation failed: quota exhausted (54004): insufficient balance"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
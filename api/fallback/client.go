@@ -0,0 +1,148 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// Engine 链中的一个引擎
+type Engine struct {
+	Name   string
+	Client api.TranslateApi
+}
+
+// Client 按顺序组合多个翻译引擎：一个引擎在自身重试之后仍然失败时，把同一块字幕交给下一个引擎。
+// 返回条数不一致不算失败，交给调用方拆分处理。
+// 认证失败、额度用尽等无法通过重试恢复的错误（api.IsPermanent）会使该引擎在本次运行中停用。
+type Client struct {
+	engines []Engine
+	logger  *zap.SugaredLogger
+
+	mu       sync.Mutex
+	disabled map[string]error
+}
+
+func NewClient(engines []Engine, logger *zap.SugaredLogger) *Client {
+	return &Client{
+		engines: engines,
+		logger:  logger,
+	}
+}
+
+// Translate 全部引擎失败时返回各引擎的错误。仍有可用的引擎时返回可恢复的 *api.Error，
+// 类型取最后尝试的引擎的错误，未分类的错误视为临时错误；只有所有引擎都已停用时才返回永久性错误。
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	var errs, permanent []error
+	for i, e := range c.engines {
+		if err := c.disabledErr(e.Name); err != nil {
+			permanent = append(permanent, fmt.Errorf("%s: %w", e.Name, err))
+			continue
+		}
+		result, err := e.Client.Translate(ctx, text, sourceLang, targetLang)
+		if err == nil {
+			if t, ok := ctx.Value(trackerKey{}).(*Tracker); ok {
+				t.set(e.Name)
+			}
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if api.IsPermanent(err) {
+			permanent = append(permanent, fmt.Errorf("%s: %w", e.Name, err))
+			c.disable(e.Name, err)
+		} else {
+			errs = append([]error{fmt.Errorf("%s: %w", e.Name, err)}, errs...)
+		}
+		if i+1 < len(c.engines) {
+			c.logger.Warnf("Engine %s failed, falling back to %s: %s", e.Name, c.engines[i+1].Name, err)
+		}
+	}
+	err := errors.Join(append(errs, permanent...)...)
+	if len(errs) == 0 {
+		return nil, err
+	}
+	recoverable := errors.Join(errs...)
+	kind := api.KindOf(recoverable)
+	if kind == api.KindUnknown {
+		kind = api.KindTransient
+	}
+	return nil, &api.Error{Kind: kind, RetryAfter: api.RetryAfter(recoverable), Err: err}
+}
+
+func (c *Client) disable(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled == nil {
+		c.disabled = map[string]error{}
+	}
+	if _, ok := c.disabled[name]; !ok {
+		c.logger.Warnf("Engine %s disabled for the rest of the run: %s", name, err)
+		c.disabled[name] = err
+	}
+}
+
+func (c *Client) disabledErr(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.disabled[name]
+}
+
+// Sequential 任一引擎需要串行时整条链串行
+func (c *Client) Sequential() bool {
+	for _, e := range c.engines {
+		if api.IsSequential(e.Client) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits 块可能交给链中任一引擎，取各引擎限制中最严格的
+func (c *Client) Limits() api.Limits {
+	var limits api.Limits
+	for _, e := range c.engines {
+		limits = limits.Min(api.LimitsOf(e.Client))
+	}
+	return limits
+}
+
+func (c *Client) Close() error {
+	var errs []error
+	for _, e := range c.engines {
+		errs = append(errs, e.Client.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type trackerKey struct{}
+
+// Tracker 记录一次请求最终由哪个引擎完成
+type Tracker struct {
+	mu     sync.Mutex
+	engine string
+}
+
+// Track 返回带有 Tracker 的上下文，请求结束后通过 Engine 查询结果
+func Track(ctx context.Context) (context.Context, *Tracker) {
+	t := &Tracker{}
+	return context.WithValue(ctx, trackerKey{}, t), t
+}
+
+func (t *Tracker) set(engine string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.engine = engine
+}
+
+// Engine 完成请求的引擎，请求未经过引擎链（如命中缓存）时为空
+func (t *Tracker) Engine() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.engine
+}
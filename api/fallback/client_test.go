@@ -0,0 +1,144 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+type fakeEngine struct {
+	err        error
+	sequential bool
+	calls      int
+	closed     bool
+}
+
+func (f *fakeEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = strings.ToUpper(t)
+	}
+	return result, nil
+}
+
+func (f *fakeEngine) Sequential() bool {
+	return f.sequential
+}
+
+func (f *fakeEngine) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestClient_Translate(t *testing.T) {
+	errLimited := errors.New("rate limited")
+	tests := []struct {
+		name       string
+		errs       []error
+		want       []string
+		wantEngine string
+		wantCalls  []int
+		wantErr    bool
+	}{
+		{name: "first engine", errs: []error{nil, nil}, want: []string{"A"}, wantEngine: "deeplx", wantCalls: []int{1, 0}},
+		{name: "fallback", errs: []error{errLimited, nil}, want: []string{"A"}, wantEngine: "baidu", wantCalls: []int{1, 1}},
+		{name: "all failed", errs: []error{errLimited, errLimited}, wantCalls: []int{1, 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deeplx, baidu := &fakeEngine{err: tt.errs[0]}, &fakeEngine{err: tt.errs[1]}
+			c := NewClient([]Engine{{Name: "deeplx", Client: deeplx}, {Name: "baidu", Client: baidu}}, zap.NewNop().Sugar())
+			ctx, used := Track(context.Background())
+			got, err := c.Translate(ctx, []string{"a"}, "ja", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errLimited) {
+				t.Errorf("Translate() error = %v, want wrapped engine errors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+			if used.Engine() != tt.wantEngine {
+				t.Errorf("Engine() = %q, want %q", used.Engine(), tt.wantEngine)
+			}
+			if calls := []int{deeplx.calls, baidu.calls}; !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClient_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	deeplx, baidu := &fakeEngine{err: context.Canceled}, &fakeEngine{}
+	c := NewClient([]Engine{{Name: "deeplx", Client: deeplx}, {Name: "baidu", Client: baidu}}, zap.NewNop().Sugar())
+	if _, err := c.Translate(ctx, []string{"a"}, "ja", "zh"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Translate() error = %v, want context.Canceled", err)
+	}
+	if baidu.calls != 0 {
+		t.Errorf("fallback engine called after cancellation")
+	}
+}
+
+func TestClient_SequentialClose(t *testing.T) {
+	deeplx, chatgpt := &fakeEngine{}, &fakeEngine{sequential: true}
+	c := NewClient([]Engine{{Name: "deeplx", Client: deeplx}, {Name: "chatgpt", Client: chatgpt}}, zap.NewNop().Sugar())
+	if !c.Sequential() {
+		t.Errorf("Sequential() = false, want true when any engine is sequential")
+	}
+	if err := c.Close(); err != nil || !deeplx.closed || !chatgpt.closed {
+		t.Errorf("Close() = %v, closed = %v %v", err, deeplx.closed, chatgpt.closed)
+	}
+}
+
+func TestClient_disable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind api.ErrorKind
+	}{
+		{name: "rate limited", err: api.ErrRateLimited, wantKind: api.KindRateLimited},
+		{name: "unclassified", err: errors.New("boom"), wantKind: api.KindTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deeplx, baidu := &fakeEngine{err: api.ErrAuth}, &fakeEngine{err: tt.err}
+			c := NewClient([]Engine{{Name: "deeplx", Client: deeplx}, {Name: "baidu", Client: baidu}}, zap.NewNop().Sugar())
+			for i := 0; i < 2; i++ {
+				_, err := c.Translate(context.Background(), []string{"a"}, "ja", "zh")
+				if got := api.KindOf(err); got != tt.wantKind {
+					t.Errorf("KindOf() = %v, want recoverable %v", got, tt.wantKind)
+				}
+				if api.IsPermanent(err) {
+					t.Errorf("IsPermanent(%v) = true while baidu is still enabled", err)
+				}
+				if !errors.Is(err, api.ErrAuth) || !errors.Is(err, tt.err) {
+					t.Errorf("Translate() error = %v, want wrapped engine errors", err)
+				}
+			}
+			if deeplx.calls != 1 || baidu.calls != 2 {
+				t.Errorf("calls = %d, %d, want 1, 2", deeplx.calls, baidu.calls)
+			}
+		})
+	}
+}
+
+func TestClient_allDisabled(t *testing.T) {
+	deeplx, baidu := &fakeEngine{err: api.ErrAuth}, &fakeEngine{err: api.ErrQuota}
+	c := NewClient([]Engine{{Name: "deeplx", Client: deeplx}, {Name: "baidu", Client: baidu}}, zap.NewNop().Sugar())
+	_, err := c.Translate(context.Background(), []string{"a"}, "ja", "zh")
+	if !api.IsPermanent(err) {
+		t.Errorf("IsPermanent(%v) = false, want true", err)
+	}
+}
@@ -0,0 +1,96 @@
+package glossary
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AnTengye/srtt/api"
+)
+
+// placeholderRe 匹配占位符，容忍引擎在括号内外加入空格
+var placeholderRe = regexp.MustCompile(`\{\s*(\d+)\s*\}`)
+
+// Protect 把术语替换为 {1}、{2} 这样的占位符，返回替换后的文本和每个占位符对应的术语。
+// 原文本身含有类似占位符的内容时不做替换，避免还原时混淆。
+func (g *Glossary) Protect(text string) (string, []*Entry) {
+	if placeholderRe.MatchString(text) {
+		return text, nil
+	}
+	found := g.find(text)
+	if len(found) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	entries := make([]*Entry, 0, len(found))
+	last := 0
+	for i, m := range found {
+		b.WriteString(text[last:m.start])
+		fmt.Fprintf(&b, "{%d}", i+1)
+		entries = append(entries, m.entry)
+		last = m.end
+	}
+	b.WriteString(text[last:])
+	return b.String(), entries
+}
+
+// Restore 把占位符替换回术语表规定的译法，无法识别的占位符原样保留
+func Restore(text string, entries []*Entry) string {
+	if len(entries) == 0 {
+		return text
+	}
+	return placeholderRe.ReplaceAllStringFunc(text, func(s string) string {
+		n, err := strconv.Atoi(placeholderRe.FindStringSubmatch(s)[1])
+		if err != nil || n < 1 || n > len(entries) {
+			return s
+		}
+		return entries[n-1].Target
+	})
+}
+
+// Client 翻译前用占位符保护术语，翻译后还原为指定译法，用于不能接收术语表提示词的引擎
+type Client struct {
+	inner    api.TranslateApi
+	glossary *Glossary
+}
+
+func NewClient(inner api.TranslateApi, glossary *Glossary) *Client {
+	return &Client{
+		inner:    inner,
+		glossary: glossary,
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	protected := make([]string, len(text))
+	entries := make([][]*Entry, len(text))
+	for i, t := range text {
+		protected[i], entries[i] = c.glossary.Protect(t)
+	}
+	result, err := c.inner.Translate(ctx, protected, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	// 条数不一致时无法确定占位符属于哪条字幕，原样返回交给对齐校验处理
+	if len(result) != len(text) {
+		return result, nil
+	}
+	for i := range result {
+		result[i] = Restore(result[i], entries[i])
+	}
+	return result, nil
+}
+
+func (c *Client) Sequential() bool {
+	return api.IsSequential(c.inner)
+}
+
+func (c *Client) Limits() api.Limits {
+	return api.LimitsOf(c.inner)
+}
+
+func (c *Client) Close() error {
+	return c.inner.Close()
+}
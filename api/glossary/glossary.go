@@ -0,0 +1,310 @@
+package glossary
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 匹配方式
+const (
+	// MatchWord 术语两侧不能紧挨字母或数字；中日韩文字没有词边界，按子串匹配
+	MatchWord = "word"
+	// MatchSubstring 出现在任意位置都算匹配
+	MatchSubstring = "substring"
+)
+
+// Entry 术语表中的一条。Target 为空时表示保留原文不翻译
+type Entry struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+	// Case 为 true 时区分大小写
+	Case bool `yaml:"case"`
+	// Match 为 word 或 substring，默认 word
+	Match string `yaml:"match"`
+
+	re *regexp.Regexp
+}
+
+// Glossary 按原文长度从长到短排列的术语表，长术语优先匹配
+type Glossary struct {
+	Entries []Entry
+}
+
+// Load 根据扩展名读取 .csv、.tsv/.txt 或 .yaml/.yml 术语表
+func Load(path string) (*Glossary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = parseTable(f, ',')
+	case ".tsv", ".txt":
+		entries, err = parseTable(f, '\t')
+	case ".yaml", ".yml":
+		entries, err = parseYAML(f)
+	default:
+		return nil, fmt.Errorf("unsupported glossary format: %s, want .csv, .tsv or .yaml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("glossary %s: %w", path, err)
+	}
+	return New(entries)
+}
+
+// New 校验条目并编译匹配规则
+func New(entries []Entry) (*Glossary, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("glossary is empty")
+	}
+	g := &Glossary{Entries: make([]Entry, 0, len(entries))}
+	for i, e := range entries {
+		e.Source = strings.TrimSpace(e.Source)
+		e.Target = strings.TrimSpace(e.Target)
+		e.Match = strings.ToLower(strings.TrimSpace(e.Match))
+		if e.Source == "" {
+			return nil, fmt.Errorf("entry %d: empty source term", i+1)
+		}
+		if e.Target == "" {
+			e.Target = e.Source
+		}
+		switch e.Match {
+		case "":
+			e.Match = MatchWord
+		case MatchWord, MatchSubstring:
+		default:
+			return nil, fmt.Errorf("entry %d: unknown match %q, want word or substring", i+1, e.Match)
+		}
+		pattern := regexp.QuoteMeta(e.Source)
+		if !e.Case {
+			pattern = "(?i)" + pattern
+		}
+		e.re = regexp.MustCompile(pattern)
+		g.Entries = append(g.Entries, e)
+	}
+	sort.SliceStable(g.Entries, func(i, j int) bool {
+		return len([]rune(g.Entries[i].Source)) > len([]rune(g.Entries[j].Source))
+	})
+	return g, nil
+}
+
+// parseTable 读取 source,target[,case,match] 格式的表格，首行为表头时跳过，忽略 # 开头的注释
+func parseTable(r io.Reader, sep rune) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if sep == '\t' {
+		cr.LazyQuotes = true
+	}
+	var entries []Entry
+	for n := 0; ; n++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "source") {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("line %d: want source,target[,case,match]", line)
+		}
+		e := Entry{Source: record[0], Target: record[1]}
+		if len(record) > 2 {
+			if e.Case, err = parseCase(record[2]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if len(record) > 3 {
+			e.Match = record[3]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// parseCase 接受 sensitive/insensitive、true/false、yes/no，留空为不区分大小写
+func parseCase(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "insensitive", "false", "no", "0":
+		return false, nil
+	case "sensitive", "true", "yes", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown case %q, want sensitive or insensitive", s)
+}
+
+// parseYAML 读取条目列表，或带 terms 字段的对象
+func parseYAML(r io.Reader) ([]Entry, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Terms []Entry `yaml:"terms"`
+	}
+	if err := yaml.Unmarshal(b, &doc); err == nil && len(doc.Terms) > 0 {
+		return doc.Terms, nil
+	}
+	var entries []Entry
+	if err := yaml.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// UnmarshalYAML case 字段同时接受布尔值和 sensitive/insensitive
+func (e *Entry) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Source string `yaml:"source"`
+		Target string `yaml:"target"`
+		Case   string `yaml:"case"`
+		Match  string `yaml:"match"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	c, err := parseCase(raw.Case)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*e = Entry{Source: raw.Source, Target: raw.Target, Case: c, Match: raw.Match}
+	return nil
+}
+
+// Digest 术语表内容的摘要，用于区分缓存
+func (g *Glossary) Digest() string {
+	h := sha256.New()
+	for _, e := range g.Entries {
+		fmt.Fprintf(h, "%s\x00%s\x00%t\x00%s\n", e.Source, e.Target, e.Case, e.Match)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// match 一处术语匹配，位置为字节偏移
+type match struct {
+	start, end int
+	entry      *Entry
+}
+
+// find 查找 text 中互不重叠的术语，长术语优先，结果按位置排序
+func (g *Glossary) find(text string) []match {
+	var found []match
+	for i := range g.Entries {
+		e := &g.Entries[i]
+		for _, loc := range e.re.FindAllStringIndex(text, -1) {
+			if e.Match == MatchWord && !atBoundary(text, loc[0], loc[1]) {
+				continue
+			}
+			if overlaps(found, loc[0], loc[1]) {
+				continue
+			}
+			found = append(found, match{start: loc[0], end: loc[1], entry: e})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].start < found[j].start })
+	return found
+}
+
+func overlaps(found []match, start, end int) bool {
+	for _, m := range found {
+		if start < m.end && m.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// atBoundary 检查 text[start:end] 两侧是否为词边界
+func atBoundary(text string, start, end int) bool {
+	term := text[start:end]
+	first, _ := firstRune(term)
+	last, _ := lastRune(term)
+	if before, ok := lastRune(text[:start]); ok && isWordRune(first) && isWordRune(before) {
+		return false
+	}
+	if after, ok := firstRune(text[end:]); ok && isWordRune(last) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+func firstRune(s string) (rune, bool) {
+	for _, r := range s {
+		return r, true
+	}
+	return 0, false
+}
+
+func lastRune(s string) (rune, bool) {
+	r := []rune(s)
+	if len(r) == 0 {
+		return 0, false
+	}
+	return r[len(r)-1], true
+}
+
+// isWordRune 是否属于用空格分词的文字；中日韩文字不分词，不参与边界判断
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Violation 原文含有术语，但译文没有使用指定译法
+type Violation struct {
+	// Cue 字幕序号，从 0 开始
+	Cue    int
+	Source string
+	Target string
+}
+
+// Check 逐条检查译文是否使用了术语表规定的译法，未翻译的字幕跳过
+func (g *Glossary) Check(source []string, translated []string) []Violation {
+	var violations []Violation
+	for i, text := range source {
+		if i >= len(translated) || strings.TrimSpace(translated[i]) == "" {
+			continue
+		}
+		seen := map[*Entry]bool{}
+		for _, m := range g.find(text) {
+			if seen[m.entry] {
+				continue
+			}
+			seen[m.entry] = true
+			if !m.entry.honored(translated[i]) {
+				violations = append(violations, Violation{Cue: i, Source: m.entry.Source, Target: m.entry.Target})
+			}
+		}
+	}
+	return violations
+}
+
+func (e *Entry) honored(translated string) bool {
+	if e.Case {
+		return strings.Contains(translated, e.Target)
+	}
+	return strings.Contains(strings.ToLower(translated), strings.ToLower(e.Target))
+}
@@ -0,0 +1,153 @@
+package glossary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	want := []Entry{
+		{Source: "Uzumaki Naruto", Target: "漩涡鸣人", Match: MatchWord},
+		{Source: "Konoha", Target: "木叶", Case: true, Match: MatchSubstring},
+		{Source: "NARUTO", Target: "NARUTO", Match: MatchWord},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{name: "csv", file: "g.csv", content: "source,target,case,match\n# comment\nKonoha,木叶,sensitive,substring\n\"Uzumaki Naruto\",漩涡鸣人\nNARUTO,\n"},
+		{name: "tsv", file: "g.tsv", content: "Konoha\t木叶\ttrue\tsubstring\nUzumaki Naruto\t漩涡鸣人\nNARUTO\t\n"},
+		{name: "yaml list", file: "g.yaml", content: "- source: Konoha\n  target: 木叶\n  case: sensitive\n  match: substring\n- source: Uzumaki Naruto\n  target: 漩涡鸣人\n- source: NARUTO\n"},
+		{name: "yaml terms", file: "g.yml", content: "terms:\n  - {source: Konoha, target: 木叶, case: true, match: substring}\n  - {source: Uzumaki Naruto, target: 漩涡鸣人}\n  - {source: NARUTO}\n"},
+		{name: "bad match", file: "g.csv", content: "Konoha,木叶,,prefix\n", wantErr: true},
+		{name: "bad case", file: "g.csv", content: "Konoha,木叶,maybe\n", wantErr: true},
+		{name: "empty", file: "g.csv", content: "source,target\n", wantErr: true},
+		{name: "unknown format", file: "g.json", content: "[]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			g, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make([]Entry, len(g.Entries))
+			for i, e := range g.Entries {
+				e.re = nil
+				got[i] = e
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Load() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func testGlossary(t *testing.T) *Glossary {
+	g, err := New([]Entry{
+		{Source: "Naruto", Target: "鸣人"},
+		{Source: "Uzumaki Naruto", Target: "漩涡鸣人"},
+		{Source: "Konoha", Target: "木叶", Case: true, Match: MatchSubstring},
+		{Source: "火影", Target: "Hokage"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGlossary_Protect(t *testing.T) {
+	g := testGlossary(t)
+	tests := []struct {
+		name       string
+		text       string
+		want       string
+		wantTarget []string
+	}{
+		{name: "longest first", text: "uzumaki naruto and Naruto", want: "{1} and {2}", wantTarget: []string{"漩涡鸣人", "鸣人"}},
+		{name: "word boundary", text: "Narutos are not Naruto", want: "Narutos are not {1}", wantTarget: []string{"鸣人"}},
+		{name: "substring and case", text: "KonohaGakure konoha", want: "{1}Gakure konoha", wantTarget: []string{"木叶"}},
+		{name: "cjk without boundary", text: "七代目火影です", want: "七代目{1}です", wantTarget: []string{"Hokage"}},
+		{name: "existing placeholder", text: "{1} Naruto", want: "{1} Naruto"},
+		{name: "no term", text: "hello", want: "hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, entries := g.Protect(tt.text)
+			if got != tt.want {
+				t.Errorf("Protect() = %q, want %q", got, tt.want)
+			}
+			var targets []string
+			for _, e := range entries {
+				targets = append(targets, e.Target)
+			}
+			if !reflect.DeepEqual(targets, tt.wantTarget) {
+				t.Errorf("Protect() targets = %v, want %v", targets, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	g := testGlossary(t)
+	_, entries := g.Protect("Uzumaki Naruto from Konoha")
+	got := Restore("{ 2 }的{1}。{3}", entries)
+	if want := "木叶的漩涡鸣人。{3}"; got != want {
+		t.Errorf("Restore() = %q, want %q", got, want)
+	}
+}
+
+func TestGlossary_Check(t *testing.T) {
+	g := testGlossary(t)
+	source := []string{"Naruto!", "Naruto and Konoha", "Konoha", "nothing"}
+	translated := []string{"鸣人！", "鸣人和木之叶", "", "什么都没有"}
+	want := []Violation{{Cue: 1, Source: "Konoha", Target: "木叶"}}
+	if got := g.Check(source, translated); !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() = %+v, want %+v", got, want)
+	}
+}
+
+type fakeEngine struct {
+	got []string
+}
+
+// Translate 模拟机器翻译：占位符前后加空格，其余文本转为大写
+func (f *fakeEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	f.got = text
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = strings.ReplaceAll(strings.ToUpper(t), "{", "{ ")
+	}
+	return result, nil
+}
+
+func (f *fakeEngine) Close() error {
+	return nil
+}
+
+func TestClient_Translate(t *testing.T) {
+	inner := &fakeEngine{}
+	c := NewClient(inner, testGlossary(t))
+	got, err := c.Translate(context.Background(), []string{"hi Naruto", "bye"}, "en", "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"hi {1}", "bye"}; !reflect.DeepEqual(inner.got, want) {
+		t.Errorf("engine got %q, want %q", inner.got, want)
+	}
+	if want := []string{"HI 鸣人", "BYE"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+}
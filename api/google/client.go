@@ -3,73 +3,121 @@ package google
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"cloud.google.com/go/translate"
 	translatev3 "cloud.google.com/go/translate/apiv3"
 	"cloud.google.com/go/translate/apiv3/translatepb"
+	"github.com/AnTengye/srtt/api"
 	"go.uber.org/zap"
 	"golang.org/x/text/language"
 	"google.golang.org/api/option"
 )
 
+const (
+	EditionBasic    = "v2"
+	EditionAdvanced = "v3"
+	defaultLocation = "global"
+	// 单次请求的文本条数上限（v2 为 128 条，v3 为 1024 条）和建议的字符数上限
+	maxSegmentsBasic    = 128
+	maxSegmentsAdvanced = 1024
+	maxChars            = 30000
+)
+
+type Config struct {
+	edition         string
+	apiKey          string
+	credentialsFile string
+	projectID       string
+	location        string
+	glossary        string
+	model           string
+	endpoint        string
+}
+
 type Client struct {
-	ctx       context.Context
-	v2Cli     *translate.Client
-	v3Cli     *translatev3.TranslationClient
-	logger    *zap.SugaredLogger
-	isBasic   bool
-	projectID string
+	cfg    *Config
+	v2Cli  *translate.Client
+	v3Cli  *translatev3.TranslationClient
+	logger *zap.SugaredLogger
 }
 
-func (c *Client) Translate(text []string, sourceLang string, targetLang string) ([]string, error) {
-	if c.isBasic {
-		return c.translateTextBasic(targetLang, text)
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	if c.cfg.edition == EditionBasic {
+		return c.translateTextBasic(ctx, sourceLang, targetLang, text)
 	}
-	return c.translateTextPro(sourceLang, targetLang, text)
+	return c.translateTextPro(ctx, sourceLang, targetLang, text)
 }
 
-func NewClient(apikey, projectID string, logger *zap.SugaredLogger, isBasic bool) *Client {
+func NewClient(logger *zap.SugaredLogger, options ...func(*Config)) (*Client, error) {
+	cfg := &Config{
+		edition:  EditionBasic,
+		location: defaultLocation,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	var opts []option.ClientOption
+	switch {
+	case cfg.apiKey != "":
+		opts = append(opts, option.WithAPIKey(cfg.apiKey))
+	case cfg.credentialsFile != "":
+		opts = append(opts, option.WithCredentialsFile(cfg.credentialsFile))
+	default:
+		logger.Infof("No api key or credentials file provided, Using Application Default Credentials")
+	}
+	if cfg.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.endpoint))
+	}
+
 	ctx := context.Background()
 	c := &Client{
-		ctx:       ctx,
-		logger:    logger,
-		isBasic:   isBasic,
-		projectID: projectID,
+		cfg:    cfg,
+		logger: logger,
 	}
-
-	if isBasic {
-		client, err := translate.NewClient(ctx, option.WithAPIKey(apikey))
+	switch cfg.edition {
+	case EditionBasic:
+		if cfg.glossary != "" {
+			return nil, fmt.Errorf("google glossaries require edition %s", EditionAdvanced)
+		}
+		client, err := translate.NewClient(ctx, opts...)
 		if err != nil {
-			logger.Errorf("translate.NewClient: %s", err)
-			return nil
+			return nil, fmt.Errorf("translate.NewClient: %w", err)
 		}
 		c.v2Cli = client
-	} else {
-		client, err := translatev3.NewTranslationClient(ctx, option.WithAPIKey(apikey))
+	case EditionAdvanced:
+		if cfg.projectID == "" {
+			return nil, fmt.Errorf("google edition %s requires a project ID", EditionAdvanced)
+		}
+		client, err := translatev3.NewTranslationClient(ctx, opts...)
 		if err != nil {
-			logger.Errorf("translatev3.NewTranslationClient: %s", err)
-			return nil
+			return nil, fmt.Errorf("translatev3.NewTranslationClient: %w", err)
 		}
 		c.v3Cli = client
+	default:
+		return nil, fmt.Errorf("unknown google edition %q, want %s or %s", cfg.edition, EditionBasic, EditionAdvanced)
 	}
-	return c
+	return c, nil
 }
 
-func (c *Client) translateTextBasic(targetLanguage string, text []string) ([]string, error) {
-	if c.v2Cli == nil {
-		return nil, fmt.Errorf("translate.TranslateClient is nil")
-	}
+func (c *Client) translateTextBasic(ctx context.Context, sourceLang string, targetLanguage string, text []string) ([]string, error) {
 	lang, err := language.Parse(targetLanguage)
 	if err != nil {
-		return nil, fmt.Errorf("language.Parse: %w", err)
+		return nil, &api.Error{Kind: api.KindInvalidLanguage, Err: err}
+	}
+	opts := &translate.Options{Format: translate.Text, Model: c.cfg.model}
+	if sourceLang != "" && !strings.EqualFold(sourceLang, "auto") {
+		if opts.Source, err = language.Parse(sourceLang); err != nil {
+			return nil, &api.Error{Kind: api.KindInvalidLanguage, Err: err}
+		}
 	}
 
-	resp, err := c.v2Cli.Translate(c.ctx, text, lang, nil)
+	resp, err := c.v2Cli.Translate(ctx, text, lang, opts)
 	if err != nil {
-		return nil, fmt.Errorf("Translate: %w", err)
+		return nil, classify("Translate", err)
 	}
-	if len(resp) == 0 {
-		return nil, fmt.Errorf("Translate returned empty response to text: %s", text)
+	if len(resp) != len(text) {
+		return nil, fmt.Errorf("Translate returned %d translations for %d texts", len(resp), len(text))
 	}
 	result := make([]string, len(resp))
 	for i, r := range resp {
@@ -79,31 +127,69 @@ func (c *Client) translateTextBasic(targetLanguage string, text []string) ([]str
 	return result, nil
 }
 
-func (c *Client) translateTextPro(sourceLang string, targetLang string, text []string) ([]string, error) {
-	if c.v3Cli == nil {
-		return nil, fmt.Errorf("translatev3.TranslationClient is nil")
-	}
+func (c *Client) translateTextPro(ctx context.Context, sourceLang string, targetLang string, text []string) ([]string, error) {
 	req := &translatepb.TranslateTextRequest{
-		Parent:             fmt.Sprintf("projects/%s/locations/global", c.projectID),
-		SourceLanguageCode: sourceLang,
+		Parent:             c.parent(),
 		TargetLanguageCode: targetLang,
 		MimeType:           "text/plain", // Mime types: "text/plain", "text/html"
 		Contents:           text,
+		Model:              c.modelName(),
+	}
+	if !strings.EqualFold(sourceLang, "auto") {
+		req.SourceLanguageCode = sourceLang
+	}
+	if c.cfg.glossary != "" {
+		req.GlossaryConfig = &translatepb.TranslateTextGlossaryConfig{Glossary: c.glossaryName()}
 	}
 
-	resp, err := c.v3Cli.TranslateText(c.ctx, req)
+	resp, err := c.v3Cli.TranslateText(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("TranslateText: %w", err)
+		return nil, classify("TranslateText", err)
 	}
 
-	result := make([]string, len(resp.GetTranslations()))
-	for i, translation := range resp.GetTranslations() {
+	translations := resp.GetTranslations()
+	if req.GlossaryConfig != nil {
+		translations = resp.GetGlossaryTranslations()
+	}
+	if len(translations) != len(text) {
+		return nil, fmt.Errorf("TranslateText returned %d translations for %d texts", len(translations), len(text))
+	}
+	result := make([]string, len(translations))
+	for i, translation := range translations {
 		result[i] = translation.GetTranslatedText()
 	}
 
 	return result, nil
 }
 
+func (c *Client) parent() string {
+	return fmt.Sprintf("projects/%s/locations/%s", c.cfg.projectID, c.cfg.location)
+}
+
+// glossaryName 术语表 ID 补全为 projects/*/locations/*/glossaries/*
+func (c *Client) glossaryName() string {
+	if strings.HasPrefix(c.cfg.glossary, "projects/") {
+		return c.cfg.glossary
+	}
+	return c.parent() + "/glossaries/" + c.cfg.glossary
+}
+
+// modelName 模型 ID 补全为 projects/*/locations/*/models/*，为空时使用默认 NMT 模型
+func (c *Client) modelName() string {
+	if c.cfg.model == "" || strings.HasPrefix(c.cfg.model, "projects/") {
+		return c.cfg.model
+	}
+	return c.parent() + "/models/" + c.cfg.model
+}
+
+// Limits 每条字幕是 contents 中的一项
+func (c *Client) Limits() api.Limits {
+	if c.cfg.edition == EditionBasic {
+		return api.Limits{MaxSegments: maxSegmentsBasic, MaxChars: maxChars}
+	}
+	return api.Limits{MaxSegments: maxSegmentsAdvanced, MaxChars: maxChars}
+}
+
 func (c *Client) Close() error {
 	if c.v2Cli != nil {
 		return c.v2Cli.Close()
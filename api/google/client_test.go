@@ -1,37 +1,136 @@
 package google
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
 
-func Test_translateText(t *testing.T) {
-	type args struct {
-		targetLanguage string
-		text           string
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 Basic (v2) REST 接口，译文为 "<target>:" + 原文
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("key") != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":403,"message":"API key not valid"}}`))
+			return
+		}
+		if r.Form.Get("format") != "text" || r.Form.Get("source") != "ja" {
+			t.Errorf("unexpected request: %v", r.Form)
+		}
+		var translations []map[string]string
+		for _, q := range r.Form["q"] {
+			translations = append(translations, map[string]string{"translatedText": r.Form.Get("target") + ":" + q})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"translations": translations}})
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name     string
+		apiKey   string
+		want     []string
+		wantErr  bool
+		wantKind api.ErrorKind
+	}{
+		{name: "basic", apiKey: "key", want: []string{"zh:ちょっと、父さんに代わろう。", "zh:一行目\n二行目"}},
+		{name: "invalid key", apiKey: "bad", wantErr: true, wantKind: api.KindAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+			c, err := NewClient(logger, WithApiKey(tt.apiKey), WithEndpoint(server.URL+"/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			got, err := c.Translate(context.Background(), []string{"ちょっと、父さんに代わろう。", "一行目\n二行目"}, "ja", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
 	}
+}
+
+func TestNewClient(t *testing.T) {
+	logger := zap.NewNop().Sugar()
 	tests := []struct {
 		name    string
-		args    args
-		want    string
+		options []func(*Config)
 		wantErr bool
 	}{
+		{name: "v3 without project", options: []func(*Config){WithEdition(EditionAdvanced), WithApiKey("key")}, wantErr: true},
+		{name: "v2 with glossary", options: []func(*Config){WithApiKey("key"), WithGlossary("names")}, wantErr: true},
+		{name: "unknown edition", options: []func(*Config){WithEdition("v4"), WithApiKey("key")}, wantErr: true},
+		{name: "missing credentials file", options: []func(*Config){WithCredentialsFile("testdata/missing.json")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(logger, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				c.Close()
+			} else if c != nil {
+				t.Errorf("NewClient() returned a client with error %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_resourceNames(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantGlossary string
+		wantModel    string
+	}{
+		{
+			name:         "ids",
+			cfg:          Config{projectID: "p", location: "us-central1", glossary: "names", model: "TRL123"},
+			wantGlossary: "projects/p/locations/us-central1/glossaries/names",
+			wantModel:    "projects/p/locations/us-central1/models/TRL123",
+		},
 		{
-			name: "ja-zh",
-			args: args{
-				targetLanguage: "zh",
-				text:           "ちょっと、父さんに代わろう。",
-			},
-			want:    "",
-			wantErr: false,
+			name:         "full names",
+			cfg:          Config{projectID: "p", location: "global", glossary: "projects/q/locations/us-central1/glossaries/g", model: "projects/q/locations/us-central1/models/general/nmt"},
+			wantGlossary: "projects/q/locations/us-central1/glossaries/g",
+			wantModel:    "projects/q/locations/us-central1/models/general/nmt",
+		},
+		{
+			name:         "default model",
+			cfg:          Config{projectID: "p", location: "global", glossary: "g"},
+			wantGlossary: "projects/p/locations/global/glossaries/g",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := translateText(tt.args.targetLanguage, tt.args.text)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("translateTextBasic() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			c := &Client{cfg: &tt.cfg}
+			if got := c.glossaryName(); got != tt.wantGlossary {
+				t.Errorf("glossaryName() = %v, want %v", got, tt.wantGlossary)
 			}
-			if got != tt.want {
-				t.Errorf("translateTextBasic() got = %v, want %v", got, tt.want)
+			if got := c.modelName(); got != tt.wantModel {
+				t.Errorf("modelName() = %v, want %v", got, tt.wantModel)
 			}
 		})
 	}
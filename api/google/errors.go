@@ -0,0 +1,54 @@
+package google
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/AnTengye/srtt/api"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// classify 把 v2 REST 接口的 googleapi.Error 和 v3 gRPC 接口的状态码转换为 api.Error
+func classify(op string, err error) error {
+	e := &api.Error{Op: op, Err: err}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		h := api.HTTPError(gerr.Code, gerr.Header, "")
+		e.Kind, e.RetryAfter = h.Kind, h.RetryAfter
+		for _, item := range gerr.Errors {
+			switch item.Reason {
+			case "rateLimitExceeded", "userRateLimitExceeded":
+				e.Kind = api.KindRateLimited
+			case "dailyLimitExceeded", "quotaExceeded":
+				e.Kind = api.KindQuota
+			}
+		}
+		if gerr.Code == 400 && strings.Contains(strings.ToLower(gerr.Message), "language") {
+			e.Kind = api.KindInvalidLanguage
+		}
+		return e
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return e
+	}
+	msg := strings.ToLower(s.Message())
+	switch s.Code() {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		e.Kind = api.KindAuth
+	case codes.ResourceExhausted:
+		e.Kind = api.KindRateLimited
+	case codes.InvalidArgument:
+		switch {
+		case strings.Contains(msg, "language"):
+			e.Kind = api.KindInvalidLanguage
+		case strings.Contains(msg, "too long"), strings.Contains(msg, "exceed"):
+			e.Kind = api.KindTooLong
+		}
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Aborted:
+		e.Kind = api.KindTransient
+	}
+	return e
+}
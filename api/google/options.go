@@ -0,0 +1,55 @@
+package google
+
+// WithEdition v2（Basic）或 v3（Advanced）
+func WithEdition(edition string) func(*Config) {
+	return func(c *Config) {
+		c.edition = edition
+	}
+}
+
+func WithApiKey(apiKey string) func(*Config) {
+	return func(c *Config) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithCredentialsFile 服务账号 JSON 文件；与 api key 都为空时使用 Application Default Credentials
+func WithCredentialsFile(path string) func(*Config) {
+	return func(c *Config) {
+		c.credentialsFile = path
+	}
+}
+
+func WithProject(projectID string) func(*Config) {
+	return func(c *Config) {
+		c.projectID = projectID
+	}
+}
+
+// WithLocation v3 的区域，使用术语表或 AutoML 模型时通常为 us-central1
+func WithLocation(location string) func(*Config) {
+	return func(c *Config) {
+		c.location = location
+	}
+}
+
+// WithGlossary v3 术语表 ID 或完整资源名 projects/*/locations/*/glossaries/*
+func WithGlossary(glossary string) func(*Config) {
+	return func(c *Config) {
+		c.glossary = glossary
+	}
+}
+
+// WithModel v2 为 nmt 或 base；v3 为模型 ID（如 AutoML 模型、general/nmt）或完整资源名
+func WithModel(model string) func(*Config) {
+	return func(c *Config) {
+		c.model = model
+	}
+}
+
+// WithEndpoint 覆盖服务地址，一般只用于测试或私有网络
+func WithEndpoint(endpoint string) func(*Config) {
+	return func(c *Config) {
+		c.endpoint = endpoint
+	}
+}
@@ -0,0 +1,35 @@
+package google
+
+import (
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "google",
+		Description: "Google Cloud Translation, Basic (v2) or Advanced (v3)",
+		Settings: []api.Setting{
+			{Name: "edition", Type: api.StringSetting, Default: EditionBasic, Description: "v2 (Basic) or v3 (Advanced)"},
+			{Name: "api_key", Type: api.StringSetting, Flag: "apiKey", Description: "API key"},
+			{Name: "credentials", Type: api.StringSetting, Description: "Service account JSON file; ADC is used when neither api_key nor credentials is set"},
+			{Name: "project", Type: api.StringSetting, Flag: "apiSecret", Description: "Project ID, required by v3"},
+			{Name: "location", Type: api.StringSetting, Default: defaultLocation, Description: "v3 location, e.g. us-central1 for glossaries and AutoML models"},
+			{Name: "glossary", Type: api.StringSetting, Description: "v3 glossary ID or projects/*/locations/*/glossaries/* name"},
+			{Name: "model", Type: api.StringSetting, Description: "v2: nmt or base; v3: model ID (AutoML, general/nmt) or full resource name"},
+			{Name: "url", Type: api.StringSetting, Flag: "apiUrl", Description: "Service endpoint override"},
+		},
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(logger,
+				WithEdition(cfg.String("edition")),
+				WithApiKey(cfg.String("api_key")),
+				WithCredentialsFile(cfg.String("credentials")),
+				WithProject(cfg.String("project")),
+				WithLocation(cfg.String("location")),
+				WithGlossary(cfg.String("glossary")),
+				WithModel(cfg.String("model")),
+				WithEndpoint(cfg.String("url")),
+			)
+		},
+	})
+}
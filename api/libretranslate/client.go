@@ -0,0 +1,165 @@
+package libretranslate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl = "http://127.0.0.1:5000"
+)
+
+type Client struct {
+	apiKey  string
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+
+	mu       sync.Mutex
+	detected string
+}
+
+// NewClient apiKey 只在服务端开启 --api-keys 时需要
+func NewClient(apiKey string, logger *zap.SugaredLogger, options ...func(*resty.Client)) *Client {
+	httpClient := resty.New()
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	for _, option := range options {
+		option(httpClient)
+	}
+	if httpClient.BaseURL == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		httpClient.SetBaseURL(defaultUrl)
+	}
+	return &Client{
+		apiKey:  apiKey,
+		httpCli: httpClient,
+		logger:  logger,
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	if strings.EqualFold(sourceLang, "auto") {
+		var err error
+		if sourceLang, err = c.detect(ctx, text); err != nil {
+			return nil, err
+		}
+	}
+	var result TranslateResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(TranslateRequest{Q: text, Source: sourceLang, Target: targetLang, Format: "text", ApiKey: c.apiKey}).
+		SetResult(&result).
+		SetError(&ErrorResponse{}).
+		Post("/translate")
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, c.apiError(resp)
+	}
+	if len(result.TranslatedText) != len(text) {
+		return nil, fmt.Errorf("Translation failed: got %d translations for %d texts", len(result.TranslatedText), len(text))
+	}
+	return result.TranslatedText, nil
+}
+
+// Languages 返回服务端已加载的语言及其可翻译的目标语言
+func (c *Client) Languages(ctx context.Context) ([]Language, error) {
+	var result []Language
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetError(&ErrorResponse{}).
+		Get("/languages")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, c.apiError(resp)
+	}
+	return result, nil
+}
+
+// ValidateLanguages 确认服务端支持 source -> target，source 为 auto 时只检查目标语言
+func (c *Client) ValidateLanguages(ctx context.Context, sourceLang string, targetLang string) error {
+	languages, err := c.Languages(ctx)
+	if err != nil {
+		return err
+	}
+	var codes []string
+	for _, l := range languages {
+		codes = append(codes, l.Code)
+		if strings.EqualFold(sourceLang, "auto") && l.Code == targetLang {
+			return nil
+		}
+		if l.Code != sourceLang {
+			continue
+		}
+		for _, t := range l.Targets {
+			if t == targetLang {
+				return nil
+			}
+		}
+		return api.NewError(api.KindInvalidLanguage, "", fmt.Sprintf("libretranslate cannot translate %s to %s, available targets: %s", sourceLang, targetLang, strings.Join(l.Targets, ", ")))
+	}
+	return api.NewError(api.KindInvalidLanguage, "", fmt.Sprintf("libretranslate does not support %s -> %s, available languages: %s", sourceLang, targetLang, strings.Join(codes, ", ")))
+}
+
+// detect 首次调用时识别源语言，之后的请求沿用同一结果
+func (c *Client) detect(ctx context.Context, text []string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.detected != "" {
+		return c.detected, nil
+	}
+	var result []Detection
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(DetectRequest{Q: strings.Join(text, "\n"), ApiKey: c.apiKey}).
+		SetResult(&result).
+		SetError(&ErrorResponse{}).
+		Post("/detect")
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", c.apiError(resp)
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("libretranslate could not detect the source language")
+	}
+	c.logger.Infof("Detected source language: %s (confidence %.0f)", result[0].Language, result[0].Confidence)
+	c.detected = result[0].Language
+	return c.detected, nil
+}
+
+func (c *Client) apiError(resp *resty.Response) error {
+	msg := resp.Status()
+	if e, ok := resp.Error().(*ErrorResponse); ok && e.Error != "" {
+		msg = e.Error
+	}
+	c.logger.Errorw("Translation failed", zap.String("status", resp.Status()), zap.String("message", msg))
+	err := api.HTTPError(resp.StatusCode(), resp.Header(), msg)
+	// 如 "ja is not supported"
+	if resp.StatusCode() == http.StatusBadRequest && strings.Contains(msg, "not supported") {
+		err.Kind = api.KindInvalidLanguage
+	}
+	return err
+}
+
+func (c *Client) Close() error {
+	return nil
+}
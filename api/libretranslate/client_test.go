@@ -0,0 +1,106 @@
+package libretranslate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 LibreTranslate，译文为 "<source>-<target>:" + 原文
+func newTestServer(t *testing.T, apiKey string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/languages":
+			_, _ = w.Write([]byte(`[{"code":"en","name":"English","targets":["en","ja","zh"]},{"code":"ja","name":"Japanese","targets":["en","ja"]},{"code":"zh","name":"Chinese","targets":["en","zh"]}]`))
+		case "/detect":
+			_, _ = w.Write([]byte(`[{"confidence":90.0,"language":"ja"}]`))
+		case "/translate":
+			var req TranslateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			if req.ApiKey != apiKey {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":"Invalid API key"}`))
+				return
+			}
+			var resp TranslateResponse
+			for _, q := range req.Q {
+				resp.TranslatedText = append(resp.TranslatedText, req.Source+"-"+req.Target+":"+q)
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name       string
+		apiKey     string
+		sourceLang string
+		want       []string
+		wantErr    bool
+		wantKind   api.ErrorKind
+	}{
+		{name: "array", apiKey: "key", sourceLang: "ja", want: []string{"ja-en:一行目\n二行目", "ja-en:三行目"}},
+		{name: "auto detect", apiKey: "key", sourceLang: "auto", want: []string{"ja-en:一行目\n二行目", "ja-en:三行目"}},
+		{name: "invalid api key", apiKey: "bad", sourceLang: "ja", wantErr: true, wantKind: api.KindAuth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, "key")
+			defer server.Close()
+			c := NewClient(tt.apiKey, logger, WithBaseUrl(server.URL))
+			got, err := c.Translate(context.Background(), []string{"一行目\n二行目", "三行目"}, tt.sourceLang, "en")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ValidateLanguages(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceLang string
+		targetLang string
+		wantErr    bool
+	}{
+		{name: "supported", sourceLang: "ja", targetLang: "en"},
+		{name: "auto", sourceLang: "auto", targetLang: "zh"},
+		{name: "unsupported target", sourceLang: "ja", targetLang: "zh", wantErr: true},
+		{name: "unknown source", sourceLang: "ko", targetLang: "en", wantErr: true},
+	}
+	server := newTestServer(t, "")
+	defer server.Close()
+	c := NewClient("", zap.NewNop().Sugar(), WithBaseUrl(server.URL))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.ValidateLanguages(context.Background(), tt.sourceLang, tt.targetLang)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLanguages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, api.ErrInvalidLanguage) {
+				t.Errorf("ValidateLanguages() error = %v, want api.ErrInvalidLanguage", err)
+			}
+		})
+	}
+}
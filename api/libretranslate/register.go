@@ -0,0 +1,26 @@
+package libretranslate
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "libretranslate",
+		Description: "Self-hosted LibreTranslate server (libretranslate.com)",
+		Settings: append([]api.Setting{
+			{Name: "api_key", Type: api.StringSetting, Flag: "apiKey", Description: "API key, only needed when the server requires one"},
+		}, api.HTTPSettings(defaultUrl)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(cfg.String("api_key"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+			), nil
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package libretranslate
+
+// TranslateRequest POST /translate，q 为数组时 translatedText 也是数组
+type TranslateRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	ApiKey string   `json:"api_key,omitempty"`
+}
+
+type TranslateResponse struct {
+	TranslatedText []string `json:"translatedText"`
+}
+
+// Language GET /languages
+type Language struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+// DetectRequest POST /detect
+type DetectRequest struct {
+	Q      string `json:"q"`
+	ApiKey string `json:"api_key,omitempty"`
+}
+
+type Detection struct {
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
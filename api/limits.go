@@ -0,0 +1,145 @@
+package api
+
+import "unicode/utf8"
+
+// Tokenizer counts the tokens of text as the engine's model sees them.
+type Tokenizer func(text string) int
+
+// Limits describes how much subtitle text one request to an engine may carry.
+// Zero values mean no limit.
+type Limits struct {
+	// MaxBytes limits the UTF-8 length of the cues joined with Separator.
+	MaxBytes int
+	// MaxChars limits the number of characters of the cues joined with Separator.
+	MaxChars int
+	// MaxSegments limits the number of cues.
+	MaxSegments int
+	// MaxTokens limits the tokens of the cues joined with Separator, counted by
+	// Tokenizer or EstimateTokens when the engine has none.
+	MaxTokens int
+	Tokenizer Tokenizer
+	// Separator is what the engine puts between cues, empty when every cue is
+	// sent as its own field.
+	Separator string
+}
+
+// Limited is implemented by engines that have request size limits. The
+// pipeline packs cues into blocks that fit them.
+type Limited interface {
+	Limits() Limits
+}
+
+// LimitsOf returns the request size limits of the engine, or no limits.
+func LimitsOf(c TranslateApi) Limits {
+	if l, ok := c.(Limited); ok {
+		return l.Limits()
+	}
+	return Limits{}
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l.MaxBytes <= 0 && l.MaxChars <= 0 && l.MaxSegments <= 0 && l.MaxTokens <= 0
+}
+
+// Usage is the size of one or more cues in each unit an engine may limit.
+type Usage struct {
+	Bytes, Chars, Segments, Tokens int
+}
+
+// Add returns the combined size of u and v, not counting separators.
+func (u Usage) Add(v Usage) Usage {
+	return Usage{Bytes: u.Bytes + v.Bytes, Chars: u.Chars + v.Chars, Segments: u.Segments + v.Segments, Tokens: u.Tokens + v.Tokens}
+}
+
+// Sub returns the size of u without v.
+func (u Usage) Sub(v Usage) Usage {
+	return Usage{Bytes: u.Bytes - v.Bytes, Chars: u.Chars - v.Chars, Segments: u.Segments - v.Segments, Tokens: u.Tokens - v.Tokens}
+}
+
+// Measure returns the size of one cue. Tokens are only counted when the
+// engine limits them.
+func (l Limits) Measure(text string) Usage {
+	u := Usage{Bytes: len(text), Chars: utf8.RuneCountInString(text), Segments: 1}
+	if l.MaxTokens > 0 {
+		u.Tokens = l.tokens(text)
+	}
+	return u
+}
+
+// Allows reports whether cues of the combined size u, joined with Separator,
+// fit in one request. A single cue always fits the segment limit.
+func (l Limits) Allows(u Usage) bool {
+	if u.Segments > 1 && l.Separator != "" {
+		n := u.Segments - 1
+		sep := Usage{Bytes: len(l.Separator), Chars: utf8.RuneCountInString(l.Separator)}
+		if l.MaxTokens > 0 {
+			sep.Tokens = l.tokens(l.Separator)
+		}
+		u = u.Add(Usage{Bytes: n * sep.Bytes, Chars: n * sep.Chars, Tokens: n * sep.Tokens})
+	}
+	return within(u.Bytes, l.MaxBytes) && within(u.Chars, l.MaxChars) &&
+		within(u.Segments, l.MaxSegments) && within(u.Tokens, l.MaxTokens)
+}
+
+// Fits reports whether text fits in one request.
+func (l Limits) Fits(text []string) bool {
+	var u Usage
+	for _, t := range text {
+		u = u.Add(l.Measure(t))
+	}
+	return l.Allows(u)
+}
+
+// Min returns limits that satisfy both l and o, e.g. for a fallback chain
+// where any engine may get the block.
+func (l Limits) Min(o Limits) Limits {
+	r := Limits{
+		MaxBytes:    minLimit(l.MaxBytes, o.MaxBytes),
+		MaxChars:    minLimit(l.MaxChars, o.MaxChars),
+		MaxSegments: minLimit(l.MaxSegments, o.MaxSegments),
+		MaxTokens:   minLimit(l.MaxTokens, o.MaxTokens),
+		Tokenizer:   l.Tokenizer,
+		Separator:   l.Separator,
+	}
+	if r.MaxTokens != l.MaxTokens {
+		r.Tokenizer = o.Tokenizer
+	}
+	if len(o.Separator) > len(l.Separator) {
+		r.Separator = o.Separator
+	}
+	return r
+}
+
+func (l Limits) tokens(text string) int {
+	if l.Tokenizer != nil {
+		return l.Tokenizer(text)
+	}
+	return EstimateTokens(text)
+}
+
+// EstimateTokens approximates the token count for models without a public
+// tokenizer: one token per non-ASCII character (CJK text is rarely merged)
+// and one per four ASCII bytes.
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return other + (ascii+3)/4
+}
+
+func within(n, limit int) bool {
+	return limit <= 0 || n <= limit
+}
+
+func minLimit(a, b int) int {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
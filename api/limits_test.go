@@ -0,0 +1,44 @@
+package api
+
+import "testing"
+
+func TestLimits_Fits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		text   []string
+		want   bool
+	}{
+		{name: "no limits", text: []string{"a", "b"}, want: true},
+		{name: "separator counts", limits: Limits{MaxBytes: 8, Separator: "\n----\n"}, text: []string{"a", "b"}, want: true},
+		{name: "separator exceeds", limits: Limits{MaxBytes: 7, Separator: "\n----\n"}, text: []string{"a", "b"}, want: false},
+		{name: "chars", limits: Limits{MaxChars: 4}, text: []string{"こんにちは"}, want: false},
+		{name: "segments", limits: Limits{MaxSegments: 2}, text: []string{"a", "b", "c"}, want: false},
+		{name: "single cue", limits: Limits{MaxSegments: 1}, text: []string{"a"}, want: true},
+		{name: "tokens", limits: Limits{MaxTokens: 3}, text: []string{"こんにちは"}, want: false},
+		{name: "tokenizer", limits: Limits{MaxTokens: 3, Tokenizer: func(string) int { return 1 }}, text: []string{"こんにちは"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.Fits(tt.text); got != tt.want {
+				t.Errorf("Fits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimits_Min(t *testing.T) {
+	got := Limits{MaxBytes: 6000, Separator: "\n"}.Min(Limits{MaxBytes: 5000, MaxSegments: 50, Separator: "\n----\n"})
+	if got.MaxBytes != 5000 || got.MaxSegments != 50 || got.Separator != "\n----\n" {
+		t.Errorf("Min() = %+v", got)
+	}
+	if !(Limits{}).Min(Limits{}).IsZero() {
+		t.Error("Min() of no limits should have no limits")
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("こんにちは world"); got != 5+2 {
+		t.Errorf("EstimateTokens() = %d, want 7", got)
+	}
+}
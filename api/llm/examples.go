@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"strings"
+)
+
+// Example 翻译记忆中与待译字幕相似的参考译文
+type Example struct {
+	Source string
+	Target string
+}
+
+type examplesKey struct{}
+
+// WithExamples 把参考译文放入请求上下文，由使用提示词的引擎写入系统提示词
+func WithExamples(ctx context.Context, examples []Example) context.Context {
+	if len(examples) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, examplesKey{}, examples)
+}
+
+// ExamplesFrom 取出请求上下文中的参考译文
+func ExamplesFrom(ctx context.Context) []Example {
+	examples, _ := ctx.Value(examplesKey{}).([]Example)
+	return examples
+}
+
+// AddExamples 在系统提示词末尾追加上下文中的参考译文，没有参考译文时原样返回
+func AddExamples(ctx context.Context, system string) string {
+	examples := ExamplesFrom(ctx)
+	if len(examples) == 0 {
+		return system
+	}
+	var b strings.Builder
+	b.WriteString(system)
+	b.WriteString("\n\nReference translations of similar subtitles from earlier episodes. Reuse their wording where the meaning matches:")
+	for _, e := range examples {
+		b.WriteString("\n- ")
+		b.WriteString(strings.ReplaceAll(e.Source, "\n", " "))
+		b.WriteString(" => ")
+		b.WriteString(strings.ReplaceAll(e.Target, "\n", " "))
+	}
+	return b.String()
+}
+
+// ExampleUser 由使用提示词的引擎实现，表示会把请求上下文中的参考译文写入系统提示词
+type ExampleUser interface {
+	UsesExamples() bool
+}
@@ -0,0 +1,17 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAddExamples(t *testing.T) {
+	if got := AddExamples(context.Background(), "system"); got != "system" {
+		t.Errorf("AddExamples() without examples = %q", got)
+	}
+	ctx := WithExamples(context.Background(), []Example{{Source: "また\n明日", Target: "明天\n见"}})
+	want := "system\n\nReference translations of similar subtitles from earlier episodes. Reuse their wording where the meaning matches:\n- また 明日 => 明天 见"
+	if got := AddExamples(ctx, "system"); got != want {
+		t.Errorf("AddExamples() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,31 @@
+package llm
+
+import "strings"
+
+// Separator 多条字幕合并为一段文本时使用的分隔符
+const Separator = "\n----\n"
+
+// Join 将多条字幕合并为一段文本
+func Join(text []string) string {
+	return strings.Join(text, Separator)
+}
+
+// Split 将模型返回的文本按分隔符拆分为多条字幕
+func Split(text string) []string {
+	return strings.Split(text, Separator)
+}
+
+// finalMarker 提示词要求模型在最终译文前输出的标记
+const finalMarker = "译文终稿:"
+
+// ParseContent 去掉模型回复中 "译文终稿:" 之前的内容（包括分析过程等前言）和开头的分隔线，再拆分为多条字幕。
+// 回复中没有标记时按整段译文处理。
+func ParseContent(content string) ([]string, error) {
+	r := strings.ReplaceAll(content, "译文终稿：", finalMarker)
+	if i := strings.LastIndex(r, finalMarker); i >= 0 {
+		r = r[i+len(finalMarker):]
+	}
+	r = strings.TrimPrefix(strings.TrimSpace(r), "----")
+
+	return Split(strings.TrimSpace(r)), nil
+}
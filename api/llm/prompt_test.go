@@ -0,0 +1,31 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "final marker", content: "译文终稿:一\n----\n二", want: []string{"一", "二"}},
+		{name: "analysis before final marker", content: "译文初稿:一\n----\n二\n校对:...\n译文终稿:\n壹\n----\n贰", want: []string{"壹", "贰"}},
+		{name: "full width colon", content: "译文终稿：一\n----\n二", want: []string{"一", "二"}},
+		{name: "leading separator", content: "----\n一\n----\n二", want: []string{"一", "二"}},
+		{name: "no marker", content: "一\n----\n二\n", want: []string{"一", "二"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContent(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ParseContent() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
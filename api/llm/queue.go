@@ -0,0 +1,53 @@
+package llm
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message 一条对话消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Queue 保存最近 size 轮对话作为翻译上下文
+type Queue struct {
+	data []Message
+	size int
+}
+
+func NewQueue(size int) *Queue {
+	return &Queue{
+		data: make([]Message, 0, size*2+1),
+		size: size * 2,
+	}
+}
+
+func (q *Queue) Enqueue(msg Message) {
+	q.data = append(q.data, msg)
+	if len(q.data) > q.size {
+		q.data = q.data[2:]
+	}
+}
+
+func (q *Queue) Dequeue() Message {
+	if len(q.data) == 0 {
+		return Message{}
+	}
+	msg := q.data[0]
+	q.data = q.data[1:]
+	return msg
+}
+
+func (q *Queue) Size() int {
+	return len(q.data)
+}
+func (q *Queue) IsEmpty() bool {
+	return len(q.data) == 0
+}
+
+func (q *Queue) Get() []Message {
+	return q.data
+}
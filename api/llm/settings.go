@@ -0,0 +1,55 @@
+package llm
+
+import "github.com/AnTengye/srtt/api"
+
+// PromptSettings LLM 引擎共用的提示词设置
+func PromptSettings() []api.Setting {
+	return []api.Setting{
+		{Name: "prompt_file", Type: api.StringSetting, Flag: "prompt-file", Description: "text/template prompt file, built-in per language pair templates are used when empty"},
+		{Name: "synopsis", Type: api.StringSetting, Description: "Synopsis of the show, available to templates as {{.Synopsis}}"},
+		{Name: "style", Type: api.StringSetting, Description: "Style notes, available to templates as {{.Style}}"},
+	}
+}
+
+// PromptFromConfig 根据 PromptSettings 中的设置创建提示词
+func PromptFromConfig(cfg api.Config) (*Prompt, error) {
+	return NewPrompt(cfg.String("prompt_file"), cfg.String("synopsis"), cfg.String("style"))
+}
+
+// LimitSettings 一次请求的字幕 token 预算，为 0 时由各引擎按模型推算
+func LimitSettings() []api.Setting {
+	return []api.Setting{
+		{Name: "block_tokens", Type: api.IntSetting, Default: "0", Description: "Maximum tokens of the subtitles in one request, 0 derives it from the model"},
+	}
+}
+
+const (
+	// outputRatio 回复（分析过程和译文）的 token 数按原文的倍数估算
+	outputRatio = 2
+	// promptReserve 为系统提示词、术语表和参考译文预留的 token 数
+	promptReserve = 512
+)
+
+// BlockTokens 一次请求的字幕 token 预算：回复需要放进模型的输出上限 maxOutput，
+// 提示词、原文和回复需要一起放进上下文窗口 contextWindow；为 0 的限制不参与计算
+func BlockTokens(contextWindow, maxOutput int) int {
+	tokens := 0
+	if maxOutput > 0 {
+		tokens = maxOutput / outputRatio
+	}
+	if contextWindow > 0 {
+		n := (contextWindow - promptReserve) / (1 + outputRatio)
+		if n < 1 {
+			n = 1
+		}
+		if tokens == 0 || n < tokens {
+			tokens = n
+		}
+	}
+	return tokens
+}
+
+// Limits 按 token 预算限制一次请求的字幕，tokenizer 为空时估算 token 数
+func Limits(tokens int, tokenizer api.Tokenizer) api.Limits {
+	return api.Limits{MaxTokens: tokens, Tokenizer: tokenizer, Separator: Separator}
+}
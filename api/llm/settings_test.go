@@ -0,0 +1,24 @@
+package llm
+
+import "testing"
+
+func TestBlockTokens(t *testing.T) {
+	tests := []struct {
+		name                     string
+		contextWindow, maxOutput int
+		want                     int
+	}{
+		{name: "output bound", contextWindow: 200000, maxOutput: 4096, want: 2048},
+		{name: "context bound", contextWindow: 2048, want: 512},
+		{name: "both", contextWindow: 8192, maxOutput: 16384, want: 2560},
+		{name: "tiny context", contextWindow: 256, want: 1},
+		{name: "unlimited", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BlockTokens(tt.contextWindow, tt.maxOutput); got != tt.want {
+				t.Errorf("BlockTokens() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// StructuredRetries JSON 输出校验失败时，拆分字幕块之前的重试次数
+const StructuredRetries = 2
+
+// ErrMisaligned 模型返回的 JSON 与请求的字幕编号不一致
+var ErrMisaligned = errors.New("misaligned structured output")
+
+// EncodeCues 将字幕编码为 {"1": "...", "2": "..."}，编号从 1 开始并按顺序输出
+func EncodeCues(text []string) string {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, t := range text {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		v, _ := json.Marshal(t)
+		fmt.Fprintf(&b, "%q:%s", strconv.Itoa(i+1), v)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// CueSchema 返回 n 条字幕对应的 JSON schema，所有编号都必须出现且不允许多余的键
+func CueSchema(n int) json.RawMessage {
+	properties := make(map[string]interface{}, n)
+	required := make([]string, n)
+	for i := 0; i < n; i++ {
+		id := strconv.Itoa(i + 1)
+		properties[id] = map[string]string{"type": "string"}
+		required[i] = id
+	}
+	schema, _ := json.Marshal(map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	})
+	return schema
+}
+
+// DecodeCues 解析模型返回的 JSON 对象并校验条数和编号，忽略 JSON 前后多余的文字（如代码块标记）
+func DecodeCues(content string, n int) ([]string, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("%w: no JSON object in reply", ErrMisaligned)
+	}
+	var cues map[string]string
+	if err := json.Unmarshal([]byte(content[start:end+1]), &cues); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMisaligned, err)
+	}
+	result := make([]string, n)
+	var missing []string
+	for i := range result {
+		id := strconv.Itoa(i + 1)
+		t, ok := cues[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		result[i] = t
+		delete(cues, id)
+	}
+	if len(missing) > 0 || len(cues) > 0 {
+		extra := make([]string, 0, len(cues))
+		for id := range cues {
+			extra = append(extra, id)
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("%w: missing ids %v, unexpected ids %v", ErrMisaligned, missing, extra)
+	}
+	return result, nil
+}
+
+// Structured 调用 try 翻译一块字幕；结果校验失败（ErrMisaligned）时重试 StructuredRetries 次，
+// 仍然失败则把字幕块对半拆分后分别翻译。其他错误直接返回。
+func Structured(ctx context.Context, text []string, logger *zap.SugaredLogger, try func(ctx context.Context, text []string) ([]string, error)) ([]string, error) {
+	var err error
+	for attempt := 0; attempt <= StructuredRetries; attempt++ {
+		var result []string
+		result, err = try(ctx, text)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, ErrMisaligned) {
+			return nil, err
+		}
+		logger.Warnf("Invalid structured output for %d lines (attempt %d): %s", len(text), attempt+1, err)
+	}
+	if len(text) == 1 {
+		return nil, err
+	}
+	mid := len(text) / 2
+	logger.Infof("Splitting block of %d lines", len(text))
+	left, err := Structured(ctx, text[:mid], logger, try)
+	if err != nil {
+		return nil, err
+	}
+	right, err := Structured(ctx, text[mid:], logger, try)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
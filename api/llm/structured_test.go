@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestEncodeCues(t *testing.T) {
+	got := EncodeCues([]string{"一", "二\n\"三\""})
+	want := `{"1":"一","2":"二\n\"三\""}`
+	if got != want {
+		t.Errorf("EncodeCues() = %s, want %s", got, want)
+	}
+	var schema struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(CueSchema(3), &schema); err != nil || strings.Join(schema.Required, ",") != "1,2,3" {
+		t.Errorf("CueSchema() required = %v, err %v", schema.Required, err)
+	}
+}
+
+func TestDecodeCues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		n       int
+		want    []string
+		wantErr bool
+	}{
+		{name: "plain", content: `{"1":"a","2":"b"}`, n: 2, want: []string{"a", "b"}},
+		{name: "code fence and preamble", content: "Here you go:\n```json\n{\"2\":\"b\",\"1\":\"a\"}\n```", n: 2, want: []string{"a", "b"}},
+		{name: "missing id", content: `{"1":"a b"}`, n: 2, wantErr: true},
+		{name: "unexpected id", content: `{"1":"a","2":"b","3":"c"}`, n: 2, wantErr: true},
+		{name: "not json", content: "a\n----\nb", n: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCues(tt.content, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeCues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && !errors.Is(err, ErrMisaligned) {
+				t.Errorf("DecodeCues() error = %v, want ErrMisaligned", err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("DecodeCues() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructured(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	// 超过两条时总是漏掉最后一条，迫使字幕块拆分
+	var calls int
+	try := func(ctx context.Context, text []string) ([]string, error) {
+		calls++
+		if len(text) > 2 {
+			return nil, fmt.Errorf("%w: missing ids [%d]", ErrMisaligned, len(text))
+		}
+		r := make([]string, len(text))
+		for i, s := range text {
+			r[i] = "zh:" + s
+		}
+		return r, nil
+	}
+	got, err := Structured(context.Background(), []string{"a", "b", "c", "d"}, logger, try)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, "|") != "zh:a|zh:b|zh:c|zh:d" {
+		t.Errorf("Structured() got = %q", got)
+	}
+	if want := StructuredRetries + 1 + 2; calls != want {
+		t.Errorf("Structured() calls = %d, want %d", calls, want)
+	}
+
+	fatal := errors.New("unauthorized")
+	_, err = Structured(context.Background(), []string{"a", "b"}, logger, func(ctx context.Context, text []string) ([]string, error) {
+		return nil, fatal
+	})
+	if !errors.Is(err, fatal) {
+		t.Errorf("Structured() error = %v, want %v", err, fatal)
+	}
+}
@@ -0,0 +1,207 @@
+package llm
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+//go:embed prompts/*.tmpl
+var builtinFS embed.FS
+
+// builtin 内置提示词模板：按语言对命名（如 ja-zh），找不到时使用 default。
+// common.tmpl 中的 notes、format 等子模板也可以在自定义模板中引用。
+var builtin = template.Must(template.New("prompts").Funcs(funcs).ParseFS(builtinFS, "prompts/*.tmpl"))
+
+var funcs = template.FuncMap{
+	"name": LanguageName,
+	"zh":   languageNameZh,
+}
+
+// customName 自定义模板文件在模板集合中的名字
+const customName = "custom"
+
+// Term 术语表中的一条
+type Term struct {
+	Source string
+	Target string
+}
+
+// PromptData 提示词模板可用的变量
+type PromptData struct {
+	// SourceLang、TargetLang 为语言代码，Source、Target 为英文语言名
+	SourceLang string
+	TargetLang string
+	Source     string
+	Target     string
+	Glossary   []Term
+	Synopsis   string
+	Style      string
+	// JSON 是否使用结构化输出模式
+	JSON bool
+}
+
+// Prompt 根据语言对渲染系统提示词，同一语言对只渲染一次
+type Prompt struct {
+	tmpl     *template.Template
+	custom   bool
+	Synopsis string
+	Style    string
+	Glossary []Term
+
+	mu       sync.Mutex
+	rendered map[string]string
+}
+
+// NewPrompt path 为空时使用内置模板，否则读取 text/template 格式的模板文件
+func NewPrompt(path string, synopsis string, style string) (*Prompt, error) {
+	p := &Prompt{tmpl: builtin, Synopsis: synopsis, Style: style, rendered: map[string]string{}}
+	if path == "" {
+		return p, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := builtin.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if p.tmpl, err = tmpl.New(customName).Parse(string(b)); err != nil {
+		return nil, fmt.Errorf("prompt template %s: %w", path, err)
+	}
+	p.custom = true
+	// 用示例数据渲染一次，尽早发现模板中的错误
+	if _, err := p.System("ja", "zh", false); err != nil {
+		return nil, fmt.Errorf("prompt template %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// SetGlossary 设置写入提示词的术语表，已渲染的提示词随之失效
+func (p *Prompt) SetGlossary(terms []Term) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Glossary = terms
+	p.rendered = map[string]string{}
+}
+
+// GlossaryUser 由使用提示词的引擎实现：术语表写入系统提示词，由模型自行遵守，不再替换为占位符
+type GlossaryUser interface {
+	SetGlossary(terms []Term)
+}
+
+// System 返回指定语言对的系统提示词
+func (p *Prompt) System(sourceLang string, targetLang string, jsonMode bool) (string, error) {
+	key := fmt.Sprintf("%s|%s|%t", sourceLang, targetLang, jsonMode)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.rendered[key]; ok {
+		return s, nil
+	}
+	data := PromptData{
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Source:     LanguageName(sourceLang),
+		Target:     LanguageName(targetLang),
+		Glossary:   p.Glossary,
+		Synopsis:   p.Synopsis,
+		Style:      p.Style,
+		JSON:       jsonMode,
+	}
+	var b bytes.Buffer
+	if err := p.tmpl.ExecuteTemplate(&b, p.templateName(sourceLang, targetLang), data); err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(b.String())
+	p.rendered[key] = s
+	return s, nil
+}
+
+func (p *Prompt) templateName(sourceLang string, targetLang string) string {
+	if p.custom {
+		return customName
+	}
+	pair := baseLang(sourceLang) + "-" + baseLang(targetLang)
+	if p.tmpl.Lookup(pair) != nil {
+		return pair
+	}
+	return "default"
+}
+
+// baseLang 去掉地区并统一别名，如 zh-TW -> zh、jp -> ja
+func baseLang(code string) string {
+	code = strings.ToLower(strings.SplitN(code, "-", 2)[0])
+	if alias, ok := langAlias[code]; ok {
+		return alias
+	}
+	return code
+}
+
+var langAlias = map[string]string{
+	"jp":  "ja",
+	"kor": "ko",
+	"fra": "fr",
+	"spa": "es",
+	"ara": "ar",
+	"cht": "zh",
+	"vie": "vi",
+}
+
+type languageNames struct {
+	en string
+	zh string
+}
+
+var languages = map[string]languageNames{
+	"auto":  {"the source language", "原文"},
+	"zh":    {"Simplified Chinese", "中文"},
+	"zh-tw": {"Traditional Chinese", "繁体中文"},
+	"en":    {"English", "英语"},
+	"ja":    {"Japanese", "日语"},
+	"ko":    {"Korean", "韩语"},
+	"fr":    {"French", "法语"},
+	"de":    {"German", "德语"},
+	"es":    {"Spanish", "西班牙语"},
+	"pt":    {"Portuguese", "葡萄牙语"},
+	"it":    {"Italian", "意大利语"},
+	"ru":    {"Russian", "俄语"},
+	"ar":    {"Arabic", "阿拉伯语"},
+	"th":    {"Thai", "泰语"},
+	"vi":    {"Vietnamese", "越南语"},
+	"id":    {"Indonesian", "印尼语"},
+	"nl":    {"Dutch", "荷兰语"},
+	"pl":    {"Polish", "波兰语"},
+	"tr":    {"Turkish", "土耳其语"},
+}
+
+func lookupLanguage(code string) (languageNames, bool) {
+	code = strings.ToLower(code)
+	if code == "zh-hant" || code == "cht" {
+		code = "zh-tw"
+	}
+	if l, ok := languages[code]; ok {
+		return l, true
+	}
+	l, ok := languages[baseLang(code)]
+	return l, ok
+}
+
+// LanguageName 返回语言代码对应的英文名称，未知代码原样返回
+func LanguageName(code string) string {
+	if l, ok := lookupLanguage(code); ok {
+		return l.en
+	}
+	return code
+}
+
+func languageNameZh(code string) string {
+	if l, ok := lookupLanguage(code); ok {
+		return l.zh
+	}
+	return code
+}
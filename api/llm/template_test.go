@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrompt_System(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceLang string
+		targetLang string
+		jsonMode   bool
+		contains   []string
+		excludes   []string
+	}{
+		{name: "ja-zh keeps the proofreading persona", sourceLang: "ja", targetLang: "zh", contains: []string{"精通日语俚语", "译文终稿:", "剧情简介：两姐妹经营面包店"}},
+		{name: "jp alias", sourceLang: "jp", targetLang: "zh-CN", contains: []string{"精通日语俚语"}},
+		{name: "en-de uses the default template", sourceLang: "en", targetLang: "de", contains: []string{"English into natural, idiomatic German", "Synopsis of the show", "----"}, excludes: []string{"日语"}},
+		{name: "json mode", sourceLang: "en", targetLang: "fr", jsonMode: true, contains: []string{"JSON object", "French"}, excludes: []string{"----"}},
+		{name: "en-zh", sourceLang: "en", targetLang: "zh", contains: []string{"英译中", "键是字幕编号", "一条英语字幕"}, jsonMode: true},
+	}
+	p, err := NewPrompt("", "两姐妹经营面包店", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.System(tt.sourceLang, tt.targetLang, tt.jsonMode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("System() missing %q in:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("System() unexpected %q in:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPrompt_file(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	custom := write("custom.tmpl", `Translate {{.Source}} ({{.SourceLang}}) to {{.Target}}. Style: {{.Style}}
+{{- range .Glossary}}
+{{.Source}}={{.Target}}
+{{- end}}
+{{template "format" .}}`)
+	p, err := NewPrompt(custom, "", "formal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Glossary = []Term{{Source: "白", Target: "Shiro"}}
+	got, err := p.System("ja", "en", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Translate Japanese (ja) to English. Style: formal\n白=Shiro\n\nSubtitles are separated by lines containing only ----."
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("System() = %q, want prefix %q", got, want)
+	}
+
+	for name, content := range map[string]string{
+		"syntax.tmpl":  `{{.Source`,
+		"unknown.tmpl": `{{.Unknown}}`,
+	} {
+		if _, err := NewPrompt(write(name, content), "", ""); err == nil {
+			t.Errorf("NewPrompt(%s) error = nil", name)
+		}
+	}
+	if _, err := NewPrompt(filepath.Join(dir, "missing.tmpl"), "", ""); err == nil {
+		t.Errorf("NewPrompt(missing) error = nil")
+	}
+}
+
+func TestPrompt_SetGlossary(t *testing.T) {
+	p, err := NewPrompt("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.System("ja", "zh", false); strings.Contains(got, "术语") {
+		t.Fatalf("System() without glossary = %q", got)
+	}
+	p.SetGlossary([]Term{{Source: "白", Target: "小白"}})
+	got, err := p.System("ja", "zh", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "- 白 => 小白") {
+		t.Errorf("System() = %q, want glossary terms", got)
+	}
+}
@@ -0,0 +1,298 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl = "http://127.0.0.1:11434"
+	// defaultNumCtx 未设置 num_ctx 时 Ollama 使用的上下文长度
+	defaultNumCtx = 2048
+)
+
+type Config struct {
+	baseUrl       string
+	model         string
+	keepAlive     string
+	numCtx        int
+	temperature   float64
+	ctxOffset     int
+	pull          bool
+	completion    bool
+	jsonMode      bool
+	blockTokens   int
+	prompt        *llm.Prompt
+	retry         int
+	retryWaitTime time.Duration
+	debug         bool
+}
+
+type Client struct {
+	cfg     *Config
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+	queue   *llm.Queue
+
+	mu    sync.Mutex
+	ready bool
+}
+
+func NewClient(logger *zap.SugaredLogger, options ...func(*Config)) *Client {
+	cfg := &Config{
+		temperature: 0.2,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+	if cfg.baseUrl == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		cfg.baseUrl = defaultUrl
+	}
+	if cfg.ctxOffset == 0 {
+		cfg.ctxOffset = 10
+	}
+	if cfg.prompt == nil {
+		cfg.prompt, _ = llm.NewPrompt("", "", "")
+	}
+	httpClient := resty.New().
+		SetBaseURL(strings.TrimSuffix(cfg.baseUrl, "/")).
+		SetRetryCount(cfg.retry).
+		SetRetryWaitTime(cfg.retryWaitTime).
+		SetDebug(cfg.debug)
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	return &Client{
+		cfg:     cfg,
+		httpCli: httpClient,
+		logger:  logger,
+		queue:   llm.NewQueue(cfg.ctxOffset),
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	if err := c.ensureModel(ctx); err != nil {
+		return nil, err
+	}
+	system, err := c.cfg.prompt.System(sourceLang, targetLang, c.cfg.jsonMode)
+	if err != nil {
+		return nil, err
+	}
+	system = llm.AddExamples(ctx, system)
+	if c.cfg.jsonMode {
+		return llm.Structured(ctx, text, c.logger, func(ctx context.Context, text []string) ([]string, error) {
+			return c.translateJSON(ctx, system, text)
+		})
+	}
+	user := llm.Message{Role: llm.RoleUser, Content: llm.Join(text)}
+	messages := append([]llm.Message{{Role: llm.RoleSystem, Content: system}}, c.queue.Get()...)
+	content, err := c.generate(ctx, append(messages, user), nil)
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if content == "" {
+		c.logger.Infof("Translation result is empty")
+		return nil, nil
+	}
+	result, err := llm.ParseContent(content)
+	if err != nil || len(result) != len(text) {
+		// 条数不一致的回复会被拆分重译，不写入上下文
+		return result, err
+	}
+	c.queue.Enqueue(user)
+	c.queue.Enqueue(llm.Message{Role: llm.RoleAssistant, Content: content})
+	return result, nil
+}
+
+// translateJSON 通过 format（Ollama）或 json_schema（llama.cpp）约束模型按字幕编号输出译文
+func (c *Client) translateJSON(ctx context.Context, system string, text []string) ([]string, error) {
+	user := llm.Message{Role: llm.RoleUser, Content: llm.EncodeCues(text)}
+	messages := append([]llm.Message{{Role: llm.RoleSystem, Content: system}}, c.queue.Get()...)
+	content, err := c.generate(ctx, append(messages, user), llm.CueSchema(len(text)))
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	result, err := llm.DecodeCues(content, len(text))
+	if err != nil {
+		return nil, err
+	}
+	c.queue.Enqueue(user)
+	c.queue.Enqueue(llm.Message{Role: llm.RoleAssistant, Content: content})
+	return result, nil
+}
+
+func (c *Client) generate(ctx context.Context, messages []llm.Message, schema json.RawMessage) (string, error) {
+	if c.cfg.completion {
+		return c.complete(ctx, messages, schema)
+	}
+	return c.chat(ctx, messages, schema)
+}
+
+func (c *Client) chat(ctx context.Context, messages []llm.Message, schema json.RawMessage) (string, error) {
+	var result ChatResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(ChatRequest{
+			Model:     c.cfg.model,
+			Messages:  messages,
+			KeepAlive: c.cfg.keepAlive,
+			Options:   Options{NumCtx: c.cfg.numCtx, Temperature: c.cfg.temperature},
+			Format:    schema,
+		}).
+		SetResult(&result).
+		SetError(&result).
+		Post("/api/chat")
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", apiError(ctx, resp, result.Error)
+	}
+	return result.Message.Content, nil
+}
+
+// complete 调用 llama.cpp server 的 /completion，将对话拼接为纯文本 prompt
+func (c *Client) complete(ctx context.Context, messages []llm.Message, schema json.RawMessage) (string, error) {
+	var b strings.Builder
+	for _, m := range messages {
+		fmt.Fprintf(&b, "### %s:\n%s\n\n", m.Role, m.Content)
+	}
+	b.WriteString("### " + llm.RoleAssistant + ":\n")
+	var result CompletionResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(CompletionRequest{
+			Prompt:      b.String(),
+			Temperature: c.cfg.temperature,
+			NPredict:    -1,
+			CachePrompt: true,
+			JSONSchema:  schema,
+		}).
+		SetResult(&result).
+		SetError(&result).
+		Post("/completion")
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", apiError(ctx, resp, result.Error.Message)
+	}
+	return strings.TrimSpace(result.Content), nil
+}
+
+// contextLengthErrors 提示词超出上下文长度时 Ollama（prompt too long; exceeded max context length by N tokens）
+// 和 llama.cpp server（the request exceeds the available context size）返回的错误信息
+var contextLengthErrors = []string{"prompt too long", "max context length", "maximum context length", "available context size"}
+
+// apiError 按状态码分类；提示词超出上下文长度时归为 text too long，交给调用方拆分
+func apiError(ctx context.Context, resp *resty.Response, msg string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := api.HTTPError(resp.StatusCode(), resp.Header(), strings.TrimSpace(resp.Status()+" "+msg))
+	lower := strings.ToLower(msg)
+	for _, s := range contextLengthErrors {
+		if strings.Contains(lower, s) {
+			err.Kind = api.KindTooLong
+		}
+	}
+	return err
+}
+
+// ensureModel 首次翻译前确认模型已下载，开启 pull 时自动拉取
+func (c *Client) ensureModel(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ready || c.cfg.completion {
+		return nil
+	}
+	if c.cfg.model == "" {
+		return fmt.Errorf("ollama model is empty")
+	}
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(ShowRequest{Model: c.cfg.model}).
+		Post("/api/show")
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode() == http.StatusNotFound && c.cfg.pull:
+		c.logger.Infof("Model %s not found, pulling", c.cfg.model)
+		if err := c.pullModel(ctx); err != nil {
+			return err
+		}
+	case resp.StatusCode() == http.StatusNotFound:
+		return fmt.Errorf("model %s not found, run `ollama pull %s` or enable pull", c.cfg.model, c.cfg.model)
+	case resp.IsError():
+		return fmt.Errorf("show model %s: %s", c.cfg.model, resp.Status())
+	}
+	c.ready = true
+	return nil
+}
+
+func (c *Client) pullModel(ctx context.Context) error {
+	var result PullResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetBody(PullRequest{Model: c.cfg.model}).
+		SetResult(&result).
+		SetError(&result).
+		Post("/api/pull")
+	if err != nil {
+		return err
+	}
+	if resp.IsError() || result.Status != "success" {
+		return fmt.Errorf("pull model %s: %s %s", c.cfg.model, resp.Status(), result.Error)
+	}
+	return nil
+}
+
+// Sequential 上下文队列依赖请求顺序，不能并发翻译
+func (c *Client) Sequential() bool {
+	return true
+}
+
+// UsesExamples 实现 llm.ExampleUser
+func (c *Client) UsesExamples() bool {
+	return true
+}
+
+// SetGlossary 实现 llm.GlossaryUser，术语表写入系统提示词
+func (c *Client) SetGlossary(terms []llm.Term) {
+	c.cfg.prompt.SetGlossary(terms)
+}
+
+// Limits 提示词、原文和回复需要一起放进 num_ctx，字幕 token 预算按 llm.BlockTokens 推算；token 数为估算
+func (c *Client) Limits() api.Limits {
+	tokens := c.cfg.blockTokens
+	if tokens <= 0 {
+		numCtx := c.cfg.numCtx
+		if numCtx <= 0 {
+			numCtx = defaultNumCtx
+		}
+		tokens = llm.BlockTokens(numCtx, 0)
+	}
+	return llm.Limits(tokens, nil)
+}
+
+func (c *Client) Close() error {
+	return nil
+}
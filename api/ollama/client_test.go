@@ -0,0 +1,159 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 Ollama 与 llama.cpp server，译文为 "zh:" + 原文
+func newTestServer(t *testing.T, installed bool) *httptest.Server {
+	translate := func(messages string) string {
+		parts := llm.Split(messages)
+		for i, p := range parts {
+			parts[i] = "zh:" + p
+		}
+		return "译文终稿:" + llm.Join(parts)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/show":
+			if !installed {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"model not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/pull":
+			installed = true
+			_, _ = w.Write([]byte(`{"status":"success"}`))
+		case "/api/chat":
+			var req ChatRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			if req.Stream || req.Messages[0].Role != llm.RoleSystem {
+				t.Errorf("unexpected request: %+v", req)
+			}
+			last := req.Messages[len(req.Messages)-1]
+			content := translate(last.Content)
+			if req.Format != nil {
+				var cues map[string]string
+				if err := json.Unmarshal([]byte(last.Content), &cues); err != nil {
+					t.Fatal(err)
+				}
+				for id, text := range cues {
+					cues[id] = "zh:" + text
+				}
+				b, _ := json.Marshal(cues)
+				content = "```json\n" + string(b) + "\n```"
+			}
+			_ = json.NewEncoder(w).Encode(ChatResponse{Model: req.Model, Done: true,
+				Message: llm.Message{Role: llm.RoleAssistant, Content: content}})
+		case "/completion":
+			var req CompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatal(err)
+			}
+			prompt := strings.TrimSuffix(req.Prompt, "\n\n### assistant:\n")
+			user := prompt[strings.LastIndex(prompt, "### user:\n")+len("### user:\n"):]
+			_ = json.NewEncoder(w).Encode(CompletionResponse{Content: translate(user)})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name       string
+		installed  bool
+		pull       bool
+		completion bool
+		jsonMode   bool
+		want       []string
+		wantErr    bool
+	}{
+		{name: "chat", installed: true, want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "model missing", wantErr: true},
+		{name: "pull missing model", pull: true, want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "json", installed: true, jsonMode: true, want: []string{"zh:一行目", "zh:二行目"}},
+		{name: "llama.cpp completion", completion: true, want: []string{"zh:一行目", "zh:二行目"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.installed)
+			defer server.Close()
+			c := NewClient(logger,
+				WithBaseUrl(server.URL),
+				WithModel("qwen2.5:7b"),
+				WithPull(tt.pull),
+				WithCompletion(tt.completion),
+				WithJSON(tt.jsonMode),
+			)
+			got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Translate_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		message  string
+		wantKind api.ErrorKind
+	}{
+		{name: "context size", status: http.StatusBadRequest, message: "the request exceeds the available context size, try increasing it", wantKind: api.KindTooLong},
+		{name: "deadline", status: http.StatusInternalServerError, message: "context deadline exceeded", wantKind: api.KindTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"error":{"code":400,"message":"` + tt.message + `"}}`))
+			}))
+			defer server.Close()
+			c := NewClient(zap.NewNop().Sugar(), WithBaseUrl(server.URL), WithCompletion(true))
+			_, err := c.Translate(context.Background(), []string{"一行目"}, "ja", "zh")
+			if got := api.KindOf(err); got != tt.wantKind {
+				t.Errorf("KindOf(%v) = %v, want %v", err, got, tt.wantKind)
+			}
+			if c.queue.Size() != 0 {
+				t.Errorf("queue keeps %d messages of a failed request", c.queue.Size())
+			}
+		})
+	}
+}
+
+func TestClient_Translate_misaligned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CompletionResponse{Content: "译文终稿:zh:一行目"})
+	}))
+	defer server.Close()
+	c := NewClient(zap.NewNop().Sugar(), WithBaseUrl(server.URL), WithCompletion(true))
+	got, err := c.Translate(context.Background(), []string{"一行目", "二行目"}, "ja", "zh")
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Translate() = %q, %v", got, err)
+	}
+	if c.queue.Size() != 0 {
+		t.Errorf("queue keeps %d messages of a misaligned reply", c.queue.Size())
+	}
+}
@@ -0,0 +1,98 @@
+package ollama
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api/llm"
+)
+
+func WithBaseUrl(baseUrl string) func(*Config) {
+	return func(c *Config) {
+		c.baseUrl = baseUrl
+	}
+}
+
+func WithModel(model string) func(*Config) {
+	return func(c *Config) {
+		c.model = model
+	}
+}
+
+// WithKeepAlive 模型在内存中保留的时间，如 "5m"，"-1" 表示一直保留
+func WithKeepAlive(keepAlive string) func(*Config) {
+	return func(c *Config) {
+		c.keepAlive = keepAlive
+	}
+}
+
+// WithNumCtx 上下文窗口大小，0 使用模型默认值
+func WithNumCtx(n int) func(*Config) {
+	return func(c *Config) {
+		c.numCtx = n
+	}
+}
+
+func WithTemperature(t float64) func(*Config) {
+	return func(c *Config) {
+		c.temperature = t
+	}
+}
+
+func WithCtxOffset(l int) func(*Config) {
+	return func(c *Config) {
+		c.ctxOffset = l
+	}
+}
+
+// WithPull 模型不存在时自动拉取
+func WithPull(pull bool) func(*Config) {
+	return func(c *Config) {
+		c.pull = pull
+	}
+}
+
+// WithCompletion 使用 llama.cpp server 的 /completion 接口
+func WithCompletion(completion bool) func(*Config) {
+	return func(c *Config) {
+		c.completion = completion
+	}
+}
+
+// WithJSON 使用 JSON schema 约束输出，按字幕编号校验译文
+func WithJSON(jsonMode bool) func(*Config) {
+	return func(c *Config) {
+		c.jsonMode = jsonMode
+	}
+}
+
+func WithRetry(retry int) func(*Config) {
+	return func(c *Config) {
+		c.retry = retry
+	}
+}
+
+func WithRetryWaitTime(waitTime time.Duration) func(*Config) {
+	return func(c *Config) {
+		c.retryWaitTime = waitTime
+	}
+}
+
+func WithDebug(debug bool) func(*Config) {
+	return func(c *Config) {
+		c.debug = debug
+	}
+}
+
+// WithBlockTokens 一次请求的字幕 token 预算，为 0 时按模型推算
+func WithBlockTokens(tokens int) func(*Config) {
+	return func(c *Config) {
+		c.blockTokens = tokens
+	}
+}
+
+// WithPrompt 系统提示词模板，默认使用按语言对选择的内置模板
+func WithPrompt(prompt *llm.Prompt) func(*Config) {
+	return func(c *Config) {
+		c.prompt = prompt
+	}
+}
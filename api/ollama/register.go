@@ -0,0 +1,48 @@
+package ollama
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "ollama",
+		Description: "Local Ollama server (/api/chat) or llama.cpp server (/completion)",
+		Settings: append([]api.Setting{
+			{Name: "model", Type: api.StringSetting, Required: true, Flag: "gptModel", Description: "Model name, e.g. qwen2.5:7b"},
+			{Name: "keep_alive", Type: api.StringSetting, Description: "How long the model stays loaded, e.g. 5m or -1"},
+			{Name: "num_ctx", Type: api.IntSetting, Default: "0", Description: "Context window size, 0 uses the model default"},
+			{Name: "temperature", Type: api.FloatSetting, Default: "0.2", Description: "Sampling temperature"},
+			{Name: "ctx_offset", Type: api.IntSetting, Default: "3", Flag: "ctxOffset", Description: "Number of previous exchanges kept as context"},
+			{Name: "pull", Type: api.BoolSetting, Default: "false", Description: "Pull the model when it is not available"},
+			{Name: "completion", Type: api.BoolSetting, Default: "false", Description: "Use the llama.cpp server /completion endpoint"},
+			{Name: "json", Type: api.BoolSetting, Default: "false", Description: "Constrain output to JSON keyed by cue ID"},
+		}, append(append(llm.PromptSettings(), llm.LimitSettings()...), api.HTTPSettings(defaultUrl)...)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			prompt, err := llm.PromptFromConfig(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return NewClient(logger,
+				WithBaseUrl(cfg.String("url")),
+				WithModel(cfg.String("model")),
+				WithKeepAlive(cfg.String("keep_alive")),
+				WithNumCtx(cfg.Int("num_ctx")),
+				WithTemperature(cfg.Float("temperature")),
+				WithCtxOffset(cfg.Int("ctx_offset")),
+				WithPull(cfg.Bool("pull")),
+				WithCompletion(cfg.Bool("completion")),
+				WithJSON(cfg.Bool("json")),
+				WithBlockTokens(cfg.Int("block_tokens")),
+				WithPrompt(prompt),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+				WithDebug(cfg.Bool("debug")),
+			), nil
+		},
+	})
+}
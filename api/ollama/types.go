@@ -0,0 +1,66 @@
+package ollama
+
+import (
+	"encoding/json"
+
+	"github.com/AnTengye/srtt/api/llm"
+)
+
+// ChatRequest POST /api/chat
+type ChatRequest struct {
+	Model     string          `json:"model"`
+	Messages  []llm.Message   `json:"messages"`
+	Stream    bool            `json:"stream"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+	Options   Options         `json:"options,omitempty"`
+	Format    json.RawMessage `json:"format,omitempty"`
+}
+
+// Options 模型参数
+type Options struct {
+	NumCtx      int     `json:"num_ctx,omitempty"`
+	Temperature float64 `json:"temperature"`
+}
+
+type ChatResponse struct {
+	Model   string      `json:"model"`
+	Message llm.Message `json:"message"`
+	Done    bool        `json:"done"`
+	Error   string      `json:"error"`
+}
+
+// ShowRequest POST /api/show，模型不存在时返回 404
+type ShowRequest struct {
+	Model string `json:"model"`
+}
+
+// PullRequest POST /api/pull
+type PullRequest struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+type PullResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// CompletionRequest llama.cpp server POST /completion
+type CompletionRequest struct {
+	Prompt      string  `json:"prompt"`
+	Temperature float64 `json:"temperature"`
+	NPredict    int     `json:"n_predict"`
+	CachePrompt bool    `json:"cache_prompt"`
+	Stream      bool    `json:"stream"`
+	// JSONSchema 约束输出为符合 schema 的 JSON
+	JSONSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+type CompletionResponse struct {
+	Content string `json:"content"`
+	Error   struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SettingType is the value type of an engine setting.
+type SettingType string
+
+const (
+	StringSetting   SettingType = "string"
+	IntSetting      SettingType = "int"
+	FloatSetting    SettingType = "float"
+	BoolSetting     SettingType = "bool"
+	DurationSetting SettingType = "duration"
+)
+
+// Setting describes one configuration key of an engine.
+type Setting struct {
+	Name        string
+	Type        SettingType
+	Required    bool
+	Default     string
+	Description string
+	// Flag is the translate flag that also sets this value: either a legacy
+	// shared flag (e.g. "apiKey"), read only for the first engine of a chain,
+	// or a flag prefixed with the engine name (e.g. "azure-region").
+	Flag string
+}
+
+// Config holds validated engine settings with defaults applied.
+type Config map[string]string
+
+func (c Config) String(name string) string {
+	return c[name]
+}
+
+func (c Config) Int(name string) int {
+	n, _ := strconv.Atoi(c[name])
+	return n
+}
+
+func (c Config) Float(name string) float64 {
+	f, _ := strconv.ParseFloat(c[name], 64)
+	return f
+}
+
+func (c Config) Bool(name string) bool {
+	b, _ := strconv.ParseBool(c[name])
+	return b
+}
+
+func (c Config) Duration(name string) time.Duration {
+	d, _ := time.ParseDuration(c[name])
+	return d
+}
+
+// Engine is a translation engine that can be selected by name.
+type Engine struct {
+	Name        string
+	Description string
+	Settings    []Setting
+	New         func(cfg Config, logger *zap.SugaredLogger) (TranslateApi, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Engine{}
+)
+
+// Register makes an engine available by name. It panics if the name is registered twice.
+func Register(e Engine) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[e.Name]; dup {
+		panic("api: Register called twice for engine " + e.Name)
+	}
+	registry[e.Name] = e
+}
+
+// Lookup returns the engine registered under name.
+func Lookup(name string) (Engine, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := registry[name]
+	return e, ok
+}
+
+// Engines returns all registered engines sorted by name.
+func Engines() []Engine {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	engines := make([]Engine, 0, len(registry))
+	for _, e := range registry {
+		engines = append(engines, e)
+	}
+	sort.Slice(engines, func(i, j int) bool { return engines[i].Name < engines[j].Name })
+	return engines
+}
+
+// Setting returns the setting with the given name.
+func (e Engine) Setting(name string) (Setting, bool) {
+	for _, s := range e.Settings {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Config applies defaults to values and validates names, types and required settings.
+func (e Engine) Config(values map[string]string) (Config, error) {
+	cfg := Config{}
+	for name, v := range values {
+		s, ok := e.Setting(name)
+		if !ok {
+			return nil, fmt.Errorf("engine %s has no setting %q", e.Name, name)
+		}
+		if err := checkType(s, v); err != nil {
+			return nil, fmt.Errorf("engine %s: %w", e.Name, err)
+		}
+		cfg[name] = v
+	}
+	var missing []string
+	for _, s := range e.Settings {
+		if _, ok := cfg[s.Name]; ok && cfg[s.Name] != "" {
+			continue
+		}
+		if s.Required {
+			missing = append(missing, s.Name)
+			continue
+		}
+		cfg[s.Name] = s.Default
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("engine %s requires setting %s", e.Name, strings.Join(missing, ", "))
+	}
+	return cfg, nil
+}
+
+func checkType(s Setting, v string) error {
+	if v == "" {
+		return nil
+	}
+	var err error
+	switch s.Type {
+	case IntSetting:
+		_, err = strconv.Atoi(v)
+	case FloatSetting:
+		_, err = strconv.ParseFloat(v, 64)
+	case BoolSetting:
+		_, err = strconv.ParseBool(v)
+	case DurationSetting:
+		_, err = time.ParseDuration(v)
+	}
+	if err != nil {
+		return fmt.Errorf("setting %s must be %s, got %q", s.Name, s.Type, v)
+	}
+	return nil
+}
+
+// HTTPSettings are the settings shared by engines built on the resty options
+// pattern (WithBaseUrl, WithRetry, WithRetryWaitTime, WithDebug).
+func HTTPSettings(defaultURL string) []Setting {
+	return []Setting{
+		{Name: "url", Type: StringSetting, Default: defaultURL, Flag: "apiUrl", Description: "API base url"},
+		{Name: "retry", Type: IntSetting, Default: "3", Flag: "retry", Description: "Retry times"},
+		{Name: "retry_wait", Type: IntSetting, Default: "500", Flag: "retryWT", Description: "Retry wait time(ms)"},
+		{Name: "debug", Type: BoolSetting, Default: "false", Flag: "debug", Description: "Log HTTP requests and responses"},
+	}
+}
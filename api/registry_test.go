@@ -0,0 +1,45 @@
+package api
+
+import "testing"
+
+func TestEngine_Config(t *testing.T) {
+	e := Engine{
+		Name: "test",
+		Settings: []Setting{
+			{Name: "key", Type: StringSetting, Required: true},
+			{Name: "retry", Type: IntSetting, Default: "3"},
+			{Name: "timeout", Type: DurationSetting},
+		},
+	}
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{name: "defaults", values: map[string]string{"key": "k"}, want: Config{"key": "k", "retry": "3", "timeout": ""}},
+		{name: "override", values: map[string]string{"key": "k", "retry": "5", "timeout": "2s"}, want: Config{"key": "k", "retry": "5", "timeout": "2s"}},
+		{name: "missing required", values: map[string]string{"retry": "5"}, wantErr: true},
+		{name: "unknown", values: map[string]string{"key": "k", "foo": "bar"}, wantErr: true},
+		{name: "bad type", values: map[string]string{"key": "k", "retry": "many"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.Config(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Config() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("Config()[%s] = %q, want %q", k, got[k], v)
+				}
+			}
+			if got.Int("retry") == 0 || (tt.values["timeout"] != "" && got.Duration("timeout") == 0) {
+				t.Errorf("typed getters = %d, %v", got.Int("retry"), got.Duration("timeout"))
+			}
+		})
+	}
+}
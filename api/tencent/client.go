@@ -0,0 +1,152 @@
+package tencent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl    = "https://tmt.tencentcloudapi.com"
+	defaultRegion = "ap-guangzhou"
+	service       = "tmt"
+	action        = "TextTranslateBatch"
+	version       = "2018-03-21"
+	// maxChars 单次请求文本长度总和上限
+	maxChars = 6000
+)
+
+type Client struct {
+	secretId  string
+	secretKey string
+	httpCli   *resty.Client
+	logger    *zap.SugaredLogger
+}
+
+func NewClient(secretId string, secretKey string, logger *zap.SugaredLogger, options ...func(*resty.Client)) *Client {
+	httpClient := resty.New()
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	httpClient.SetHeaders(map[string]string{
+		"X-TC-Action":  action,
+		"X-TC-Version": version,
+		"X-TC-Region":  defaultRegion,
+	})
+	for _, option := range options {
+		option(httpClient)
+	}
+	if httpClient.BaseURL == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		httpClient.SetBaseURL(defaultUrl)
+	}
+	return &Client{
+		secretId:  secretId,
+		secretKey: secretKey,
+		httpCli:   httpClient,
+		logger:    logger,
+	}
+}
+
+// Translate 每条字幕作为 SourceTextList 的一项，译文与原文一一对应
+func (c Client) Translate(ctx context.Context, t []string, sourceLang string, targetLang string) ([]string, error) {
+	chars := 0
+	for _, text := range t {
+		chars += utf8.RuneCountInString(text)
+	}
+	if chars >= maxChars {
+		c.logger.Errorw("Translation failed", zap.String("reason", "text too long"))
+		return nil, api.NewError(api.KindTooLong, "", fmt.Sprintf("%d characters, limit is %d", chars, maxChars))
+	}
+	payload, err := json.Marshal(TextTranslateBatchRequest{
+		Source:         lang(sourceLang),
+		Target:         lang(targetLang),
+		SourceTextList: t,
+	})
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.httpCli.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := time.Now().Unix()
+	var result TextTranslateBatchResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", contentType).
+		SetHeader("X-TC-Timestamp", fmt.Sprint(timestamp)).
+		SetHeader("Authorization", sign(c.secretId, c.secretKey, service, u.Host, payload, timestamp)).
+		SetBody(payload).
+		SetResult(&result).
+		Post("/")
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		c.logger.Errorw("Translation failed", zap.String("status", resp.Status()))
+		return nil, api.HTTPError(resp.StatusCode(), resp.Header(), resp.Status())
+	}
+	if e := result.Response.Error; e != nil {
+		c.logger.Errorw("Translation failed", zap.String("code", e.Code), zap.String("message", e.Message))
+		return nil, api.NewError(errorKind(e.Code), e.Code, e.Message)
+	}
+	if len(result.Response.TargetTextList) != len(t) {
+		return nil, fmt.Errorf("Translation failed: got %d translations for %d texts", len(result.Response.TargetTextList), len(t))
+	}
+	return result.Response.TargetTextList, nil
+}
+
+// errorKind 按错误码前缀分类，见 https://cloud.tencent.com/document/api/551/30637
+func errorKind(code string) api.ErrorKind {
+	switch {
+	case strings.HasPrefix(code, "AuthFailure"),
+		code == "FailedOperation.ServiceIsolate",
+		code == "FailedOperation.UserNotRegistered",
+		code == "UnauthorizedOperation.ActionNotFound":
+		return api.KindAuth
+	case code == "FailedOperation.NoFreeAmount",
+		code == "FailedOperation.StopUsing",
+		code == "ResourceInsufficient.NoFreeAmount":
+		return api.KindQuota
+	case code == "RequestLimitExceeded", strings.HasPrefix(code, "LimitExceeded"):
+		return api.KindRateLimited
+	case strings.Contains(code, "TextTooLong"):
+		return api.KindTooLong
+	case strings.Contains(code, "Language"):
+		return api.KindInvalidLanguage
+	case strings.HasPrefix(code, "InternalError"), code == "FailedOperation.RequestAiLabErr":
+		return api.KindTransient
+	}
+	return api.KindUnknown
+}
+
+func lang(code string) string {
+	if v, ok := langMap[strings.ToLower(code)]; ok {
+		return v
+	}
+	return code
+}
+
+// Limits SourceTextList 的文本长度总和需低于 maxChars
+func (c *Client) Limits() api.Limits {
+	return api.Limits{MaxChars: maxChars - 1}
+}
+
+func (c *Client) Close() error {
+	return nil
+}
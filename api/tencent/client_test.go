@@ -0,0 +1,82 @@
+package tencent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟 TMT 接口：校验签名，译文为 "zh:" + 原文
+func newTestServer(t *testing.T, errorCode string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-TC-Timestamp"), 10, 64)
+		u, _ := url.Parse("http://" + r.Host)
+		if r.Header.Get("Authorization") != sign("id", "key", service, u.Host, payload, timestamp) {
+			t.Errorf("invalid signature")
+		}
+		if r.Header.Get("X-TC-Action") != action || r.Header.Get("X-TC-Region") != "ap-shanghai" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		var req TextTranslateBatchRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			t.Fatal(err)
+		}
+		var resp TextTranslateBatchResponse
+		if errorCode != "" {
+			resp.Response.Error = &struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			}{Code: errorCode, Message: "error"}
+		} else {
+			for _, text := range req.SourceTextList {
+				resp.Response.TargetTextList = append(resp.Response.TargetTextList, req.Target+":"+text)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name      string
+		errorCode string
+		want      []string
+		wantErr   bool
+		wantKind  api.ErrorKind
+	}{
+		{name: "batch", want: []string{"zh:一行目\n二行目", "zh:三行目"}},
+		{name: "error code", errorCode: "AuthFailure.SignatureFailure", wantErr: true, wantKind: api.KindAuth},
+		{name: "rate limited", errorCode: "RequestLimitExceeded", wantErr: true, wantKind: api.KindRateLimited},
+		{name: "unsupported language", errorCode: "UnsupportedOperation.UnsupportedLanguage", wantErr: true, wantKind: api.KindInvalidLanguage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.errorCode)
+			defer server.Close()
+			c := NewClient("id", "key", logger, WithBaseUrl(server.URL), WithRegion("ap-shanghai"))
+			got, err := c.Translate(context.Background(), []string{"一行目\n二行目", "三行目"}, "jp", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package tencent
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "tencent",
+		Description: "Tencent Cloud Machine Translation (TMT TextTranslateBatch)",
+		Settings: append([]api.Setting{
+			{Name: "secret_id", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "SecretId from the Tencent Cloud console"},
+			{Name: "secret_key", Type: api.StringSetting, Required: true, Flag: "apiSecret", Description: "SecretKey from the Tencent Cloud console"},
+			{Name: "region", Type: api.StringSetting, Default: defaultRegion, Description: "Region, e.g. ap-guangzhou"},
+		}, api.HTTPSettings(defaultUrl)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(cfg.String("secret_id"), cfg.String("secret_key"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+				WithRegion(cfg.String("region")),
+			), nil
+		},
+	})
+}
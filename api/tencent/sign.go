@@ -0,0 +1,51 @@
+package tencent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	algorithm   = "TC3-HMAC-SHA256"
+	contentType = "application/json; charset=utf-8"
+)
+
+// 签名方法 v3（TC3-HMAC-SHA256）：
+// Step1. 拼接规范请求串 CanonicalRequest：
+// HTTPRequestMethod + "\n" + CanonicalURI + "\n" + CanonicalQueryString + "\n" + CanonicalHeaders + "\n" + SignedHeaders + "\n" + HashedRequestPayload
+// Step2. 拼接待签名字符串：Algorithm + "\n" + RequestTimestamp + "\n" + CredentialScope + "\n" + HashedCanonicalRequest，
+// CredentialScope 为 Date/service/tc3_request，Date 为时间戳对应的 UTC 日期。
+// Step3. 依次计算 SecretDate = HMAC("TC3"+SecretKey, Date)、SecretService = HMAC(SecretDate, service)、
+// SecretSigning = HMAC(SecretService, "tc3_request")，Signature = hex(HMAC(SecretSigning, StringToSign))。
+// Step4. 拼接 Authorization：Algorithm Credential=SecretId/CredentialScope, SignedHeaders=..., Signature=...
+func sign(secretId, secretKey, service, host string, payload []byte, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	signedHeaders := "content-type;host"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\n", contentType, host)
+	canonicalRequest := strings.Join([]string{"POST", "/", "", canonicalHeaders, signedHeaders, sha256hex(payload)}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/tc3_request", date, service)
+	stringToSign := strings.Join([]string{algorithm, fmt.Sprint(timestamp), credentialScope, sha256hex([]byte(canonicalRequest))}, "\n")
+
+	secretDate := hmacSHA256([]byte("TC3"+secretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", algorithm, secretId, credentialScope, signedHeaders, signature)
+}
+
+func sha256hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
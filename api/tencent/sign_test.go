@@ -0,0 +1,40 @@
+package tencent
+
+import "testing"
+
+// Test_sign 使用腾讯云签名方法 v3 文档中的示例
+func Test_sign(t *testing.T) {
+	type args struct {
+		secretId  string
+		secretKey string
+		service   string
+		host      string
+		payload   string
+		timestamp int64
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "document example",
+			args: args{
+				secretId:  "AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE",
+				secretKey: "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE",
+				service:   "cvm",
+				host:      "cvm.tencentcloudapi.com",
+				payload:   `{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`,
+				timestamp: 1551113065,
+			},
+			want: "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, SignedHeaders=content-type;host, Signature=72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sign(tt.args.secretId, tt.args.secretKey, tt.args.service, tt.args.host, []byte(tt.args.payload), tt.args.timestamp); got != tt.want {
+				t.Errorf("sign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
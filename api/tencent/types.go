@@ -0,0 +1,66 @@
+package tencent
+
+// TextTranslateBatchRequest 批量文本翻译
+// 文档：https://cloud.tencent.com/document/api/551/40566
+//
+// 参数名称	必选	类型	描述
+// Source	是	String	源语言，auto 为自动识别
+// Target	是	String	目标语言
+// ProjectId	是	Integer	项目ID，没有时填 0
+// SourceTextList	是	Array of String	待翻译的文本列表，文本长度总和需要低于 6000 字符
+type TextTranslateBatchRequest struct {
+	Source         string   `json:"Source"`
+	Target         string   `json:"Target"`
+	ProjectId      int64    `json:"ProjectId"`
+	SourceTextList []string `json:"SourceTextList"`
+}
+
+type TextTranslateBatchResponse struct {
+	Response struct {
+		Source         string   `json:"Source"`
+		Target         string   `json:"Target"`
+		TargetTextList []string `json:"TargetTextList"`
+		RequestId      string   `json:"RequestId"`
+		Error          *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+	} `json:"Response"`
+}
+
+// 自动识别	auto	简体中文	zh	繁体中文	zh-TW
+// 英语	en	日语	ja	韩语	ko
+// 法语	fr	西班牙语	es	意大利语	it
+// 德语	de	土耳其语	tr	俄语	ru
+// 葡萄牙语	pt	越南语	vi	印尼语	id
+// 泰语	th	马来语	ms	阿拉伯语	ar
+// 印地语	hi
+var langMap = map[string]string{
+	"auto":  "auto",
+	"zh":    "zh",
+	"zh-cn": "zh",
+	"zh-tw": "zh-TW",
+	"cht":   "zh-TW",
+	"en":    "en",
+	"ja":    "ja",
+	"jp":    "ja",
+	"ko":    "ko",
+	"kor":   "ko",
+	"fr":    "fr",
+	"fra":   "fr",
+	"es":    "es",
+	"spa":   "es",
+	"it":    "it",
+	"de":    "de",
+	"tr":    "tr",
+	"ru":    "ru",
+	"pt":    "pt",
+	"vi":    "vi",
+	"vie":   "vi",
+	"id":    "id",
+	"th":    "th",
+	"ms":    "ms",
+	"ar":    "ar",
+	"ara":   "ar",
+	"hi":    "hi",
+}
@@ -0,0 +1,104 @@
+package tm
+
+import (
+	"context"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+// Client 翻译前查询翻译记忆：精确匹配的字幕直接使用记忆中的译文，只把其余字幕发给引擎。
+// examples 为 true 时，把相似度达到 threshold 的译文作为参考放入请求上下文，供 LLM 引擎写入提示词。
+type Client struct {
+	inner     api.TranslateApi
+	store     *Store
+	threshold float64
+	examples  int
+	logger    *zap.SugaredLogger
+}
+
+// NewClient examples 为每次请求最多附带的参考译文条数，0 表示不查询相似译文
+func NewClient(inner api.TranslateApi, store *Store, threshold float64, examples int, logger *zap.SugaredLogger) *Client {
+	return &Client{
+		inner:     inner,
+		store:     store,
+		threshold: threshold,
+		examples:  examples,
+		logger:    logger,
+	}
+}
+
+func (c *Client) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	result := make([]string, len(text))
+	var pending []string
+	var index []int
+	for i, t := range text {
+		if target, ok := c.store.Get(sourceLang, targetLang, t); ok {
+			result[i] = target
+			continue
+		}
+		pending = append(pending, t)
+		index = append(index, i)
+	}
+	if len(pending) == 0 {
+		c.logger.Debugf("Translation memory hit: %d lines", len(text))
+		return result, nil
+	}
+	if c.examples > 0 {
+		ctx = llm.WithExamples(ctx, c.similar(pending, sourceLang, targetLang))
+	}
+	translated, err := c.inner.Translate(ctx, pending, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	// 条数不一致时无法确定译文归属，原样返回交给对齐校验处理
+	if len(translated) != len(pending) {
+		c.logger.Debugf("Skip translation memory merge for misaligned result: %d lines for %d", len(translated), len(pending))
+		return translated, nil
+	}
+	for j, i := range index {
+		result[i] = translated[j]
+	}
+	return result, nil
+}
+
+// similar 收集待译字幕的相似译文，去重后按相似度保留前 examples 条
+func (c *Client) similar(text []string, sourceLang string, targetLang string) []llm.Example {
+	var matches []Match
+	seen := map[string]bool{}
+	for _, t := range text {
+		found, err := c.store.Search(sourceLang, targetLang, t, c.threshold, c.examples)
+		if err != nil {
+			c.logger.Warnw("Translation memory search failed", zap.Error(err))
+			return nil
+		}
+		for _, m := range found {
+			if !seen[m.Source] {
+				seen[m.Source] = true
+				matches = append(matches, m)
+			}
+		}
+	}
+	sortMatches(matches)
+	if len(matches) > c.examples {
+		matches = matches[:c.examples]
+	}
+	examples := make([]llm.Example, len(matches))
+	for i, m := range matches {
+		examples[i] = llm.Example{Source: m.Source, Target: m.Target}
+	}
+	return examples
+}
+
+func (c *Client) Sequential() bool {
+	return api.IsSequential(c.inner)
+}
+
+func (c *Client) Limits() api.Limits {
+	return api.LimitsOf(c.inner)
+}
+
+func (c *Client) Close() error {
+	return c.inner.Close()
+}
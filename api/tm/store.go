@@ -0,0 +1,257 @@
+package tm
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const fileName = "tm.db"
+
+// Unit 翻译记忆中的一条译文
+type Unit struct {
+	SourceLang string    `json:"-"`
+	TargetLang string    `json:"-"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	Created    time.Time `json:"created"`
+}
+
+// Match 模糊匹配结果，Score 为 0 到 1 的相似度
+type Match struct {
+	Unit
+	Score float64
+}
+
+// Store 基于 bbolt 的翻译记忆，每个语言对一个 bucket，key 为规范化后的原文
+type Store struct {
+	db   *bolt.DB
+	path string
+}
+
+// DefaultDir 默认翻译记忆目录。翻译记忆需要长期保留，放在用户配置目录而不是缓存目录
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".srtt"
+	}
+	return filepath.Join(dir, "srtt")
+}
+
+// Open 打开 dir 下的翻译记忆，不存在时创建
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fileName)
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db, path: path}, nil
+}
+
+// Path 数据库文件路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// normalize 去掉首尾空白并合并行内的连续空白，作为精确匹配的 key；换行保留，多行字幕不会匹配到单行的记录
+func normalize(text string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func bucketName(sourceLang, targetLang string) []byte {
+	return []byte(strings.ToLower(sourceLang) + "|" + strings.ToLower(targetLang))
+}
+
+func splitBucketName(name []byte) (string, string) {
+	src, tgt, _ := strings.Cut(string(name), "|")
+	return src, tgt
+}
+
+// bucketNames 返回语言对的 bucket 名，完全一致的在前，其后是地区变体（ja 匹配 TMX 导入的 ja-jp）
+func bucketNames(tx *bolt.Tx, sourceLang, targetLang string) [][]byte {
+	exact := bucketName(sourceLang, targetLang)
+	var names [][]byte
+	if tx.Bucket(exact) != nil {
+		names = append(names, exact)
+	}
+	_ = tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+		src, tgt := splitBucketName(name)
+		if !bytes.Equal(name, exact) && langMatch(src, sourceLang) && langMatch(tgt, targetLang) {
+			names = append(names, bytes.Clone(name))
+		}
+		return nil
+	})
+	return names
+}
+
+// Add 写入译文，原文相同的旧译文会被覆盖，返回写入的条数
+func (s *Store) Add(units []Unit) (int, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	n := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, u := range units {
+			key := normalize(u.Source)
+			if key == "" || strings.TrimSpace(u.Target) == "" {
+				continue
+			}
+			b, err := tx.CreateBucketIfNotExists(bucketName(u.SourceLang, u.TargetLang))
+			if err != nil {
+				return err
+			}
+			if u.Created.IsZero() {
+				u.Created = now
+			}
+			v, err := json.Marshal(u)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(key), v); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Get 精确匹配，忽略空白差异
+func (s *Store) Get(sourceLang, targetLang, text string) (string, bool) {
+	var u Unit
+	found := false
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range bucketNames(tx, sourceLang, targetLang) {
+			if v := tx.Bucket(name).Get([]byte(normalize(text))); v != nil {
+				found = json.Unmarshal(v, &u) == nil
+				return nil
+			}
+		}
+		return nil
+	})
+	return u.Target, found
+}
+
+// Search 返回相似度不低于 threshold 的译文，按相似度从高到低最多 limit 条，limit 为 0 时不限
+func (s *Store) Search(sourceLang, targetLang, text string, threshold float64, limit int) ([]Match, error) {
+	query := []rune(strings.ToLower(normalize(text)))
+	var matches []Match
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range bucketNames(tx, sourceLang, targetLang) {
+			if err := search(tx, name, query, threshold, &matches); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	sortMatches(matches)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, err
+}
+
+// search 在一个 bucket 中查找相似度不低于 threshold 的译文
+func search(tx *bolt.Tx, name []byte, query []rune, threshold float64, matches *[]Match) error {
+	src, tgt := splitBucketName(name)
+	return tx.Bucket(name).ForEach(func(k, v []byte) error {
+		candidate := []rune(strings.ToLower(string(k)))
+		// 长度相差过大时相似度不可能达到阈值，跳过编辑距离计算
+		if lengthBound(query, candidate) < threshold {
+			return nil
+		}
+		score := similarity(query, candidate)
+		if score < threshold {
+			return nil
+		}
+		var u Unit
+		if json.Unmarshal(v, &u) != nil {
+			return nil
+		}
+		u.SourceLang, u.TargetLang = src, tgt
+		*matches = append(*matches, Match{Unit: u, Score: score})
+		return nil
+	})
+}
+
+// sortMatches 按相似度从高到低排序
+func sortMatches(matches []Match) {
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+}
+
+// All 按语言对导出译文，语言代码按前缀匹配，sourceLang、targetLang 为空时不限制
+func (s *Store) All(sourceLang, targetLang string) ([]Unit, error) {
+	var units []Unit
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			src, tgt := splitBucketName(name)
+			if sourceLang != "" && !langMatch(src, sourceLang) || targetLang != "" && !langMatch(tgt, targetLang) {
+				return nil
+			}
+			return b.ForEach(func(k, v []byte) error {
+				var u Unit
+				if json.Unmarshal(v, &u) != nil {
+					return nil
+				}
+				u.SourceLang, u.TargetLang = src, tgt
+				units = append(units, u)
+				return nil
+			})
+		})
+	})
+	return units, err
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// similarity 基于编辑距离的相似度：1 - 距离 / 较长文本的长度
+func similarity(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// lengthBound 仅由长度差决定的相似度上限
+func lengthBound(a, b []rune) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	return float64(min(len(a), len(b))) / float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
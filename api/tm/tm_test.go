@@ -0,0 +1,216 @@
+package tm
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnTengye/srtt/api/llm"
+	"go.uber.org/zap"
+)
+
+func openStore(t *testing.T) *Store {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStore(t *testing.T) {
+	store := openStore(t)
+	n, err := store.Add([]Unit{
+		{SourceLang: "ja", TargetLang: "zh", Source: "おはよう ございます", Target: "早上好"},
+		{SourceLang: "ja", TargetLang: "zh", Source: "次回、ナルト大ピンチ！", Target: "下集，鸣人大危机！"},
+		{SourceLang: "ja", TargetLang: "en", Source: "おはよう ございます", Target: "Good morning"},
+		{SourceLang: "ja", TargetLang: "zh", Source: "empty", Target: " "},
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Add() = %d, %v", n, err)
+	}
+	if got, ok := store.Get("JA", "zh", " おはよう\tございます "); !ok || got != "早上好" {
+		t.Errorf("Get() = %q, %v", got, ok)
+	}
+	if _, ok := store.Get("ja", "zh", "おはよう"); ok {
+		t.Errorf("Get() should only return exact matches")
+	}
+	if _, ok := store.Get("ja", "zh", "おはよう\nございます"); ok {
+		t.Errorf("Get() should keep line breaks")
+	}
+
+	matches, err := store.Search("ja", "zh", "次回、サスケ大ピンチ！", 0.7, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Target != "下集，鸣人大危机！" || matches[0].Score < 0.7 || matches[0].Score >= 1 {
+		t.Errorf("Search() = %+v", matches)
+	}
+	if matches, _ := store.Search("ja", "zh", "まったく違う文です", 0.7, 5); len(matches) != 0 {
+		t.Errorf("Search() = %+v, want no match", matches)
+	}
+
+	all, err := store.All("ja", "")
+	if err != nil || len(all) != 3 {
+		t.Errorf("All() = %+v, %v", all, err)
+	}
+	if all, _ := store.All("", "en"); len(all) != 1 || all[0].Target != "Good morning" || all[0].SourceLang != "ja" {
+		t.Errorf("All(en) = %+v", all)
+	}
+}
+
+func Test_similarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"abcd", "abcd", 1},
+		{"abcd", "abce", 0.75},
+		{"ab", "abcd", 0.5},
+		{"", "", 1},
+	}
+	for _, tt := range tests {
+		if got := similarity([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTMX(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	units := []Unit{
+		{SourceLang: "ja", TargetLang: "zh", Source: "A & B", Target: "甲<乙>", Created: created},
+		{SourceLang: "ja", TargetLang: "zh", Source: "二行\n目", Target: "两行\n目", Created: created},
+	}
+	var b bytes.Buffer
+	if err := WriteTMX(&b, units); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `<tuv xml:lang="ja">`) || !strings.Contains(b.String(), `srclang="ja"`) {
+		t.Errorf("WriteTMX() = %s", b.String())
+	}
+	got, err := ReadTMX(&b, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, units) {
+		t.Errorf("ReadTMX() = %+v, want %+v", got, units)
+	}
+}
+
+func TestReadTMX(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<tmx version="1.4"><header srclang="ja-JP"/><body>
+<tu><tuv xml:lang="ja-JP"><seg>こんにちは</seg></tuv><tuv xml:lang="zh-CN"><seg>你好</seg></tuv><tuv xml:lang="en-US"><seg>Hello</seg></tuv></tu>
+<tu><tuv lang="JA-JP"><seg>さようなら</seg></tuv><tuv lang="ZH-CN"><seg>再见</seg></tuv></tu>
+<tu><tuv xml:lang="zh-CN"><seg>只有译文</seg></tuv></tu>
+</body></tmx>`
+	got, err := ReadTMX(strings.NewReader(doc), "ja", "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Unit{
+		{SourceLang: "ja", TargetLang: "zh", Source: "こんにちは", Target: "你好"},
+		{SourceLang: "ja", TargetLang: "zh", Source: "さようなら", Target: "再见"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTMX() = %+v, want %+v", got, want)
+	}
+	all, err := ReadTMX(strings.NewReader(doc), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[1].TargetLang != "en-us" {
+		t.Errorf("ReadTMX() without languages = %+v", all)
+	}
+	if _, err := ReadTMX(strings.NewReader(`<tmx><header srclang="*all*"/><body/></tmx>`), "", ""); err == nil {
+		t.Error("ReadTMX() should require a source language")
+	}
+}
+
+type fakeEngine struct {
+	got      []string
+	examples []llm.Example
+}
+
+func (f *fakeEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	f.got = text
+	f.examples = llm.ExamplesFrom(ctx)
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = strings.ToUpper(t)
+	}
+	return result, nil
+}
+
+func (f *fakeEngine) Close() error {
+	return nil
+}
+
+func TestClient_Translate(t *testing.T) {
+	store := openStore(t)
+	if _, err := store.Add([]Unit{
+		{SourceLang: "en", TargetLang: "zh", Source: "previously on the show", Target: "前情提要"},
+		{SourceLang: "en", TargetLang: "zh", Source: "see you next week", Target: "下周见"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	inner := &fakeEngine{}
+	c := NewClient(inner, store, 0.7, 2, zap.NewNop().Sugar())
+	got, err := c.Translate(context.Background(), []string{"previously on the show", "hello", "see you next weeks"}, "en", "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"前情提要", "HELLO", "SEE YOU NEXT WEEKS"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+	if want := []string{"hello", "see you next weeks"}; !reflect.DeepEqual(inner.got, want) {
+		t.Errorf("engine got %q, want %q", inner.got, want)
+	}
+	if want := []llm.Example{{Source: "see you next week", Target: "下周见"}}; !reflect.DeepEqual(inner.examples, want) {
+		t.Errorf("examples = %+v, want %+v", inner.examples, want)
+	}
+
+	inner.got = nil
+	if _, err := c.Translate(context.Background(), []string{"see you  next week"}, "en", "zh"); err != nil {
+		t.Fatal(err)
+	}
+	if inner.got != nil {
+		t.Errorf("engine called for exact matches: %q", inner.got)
+	}
+}
+
+// TestClient_Translate_regionalTMX 不指定语言导入的 TMX 按 ja-jp|zh-cn 存储，翻译 ja 到 zh 时仍能匹配
+func TestClient_Translate_regionalTMX(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<tmx version="1.4"><header srclang="ja-JP"/><body>
+<tu><tuv xml:lang="ja-JP"><seg>前回のあらすじ</seg></tuv><tuv xml:lang="zh-CN"><seg>前情提要</seg></tuv></tu>
+<tu><tuv xml:lang="ja-JP"><seg>また来週</seg></tuv><tuv xml:lang="zh-CN"><seg>下周见</seg></tuv></tu>
+</body></tmx>`
+	units, err := ReadTMX(strings.NewReader(doc), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := openStore(t)
+	if _, err := store.Add(units); err != nil {
+		t.Fatal(err)
+	}
+	inner := &fakeEngine{}
+	c := NewClient(inner, store, 0.5, 2, zap.NewNop().Sugar())
+	got, err := c.Translate(context.Background(), []string{"前回のあらすじ", "また来週ね"}, "ja", "zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"前情提要", "また来週ね"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Translate() = %q, want %q", got, want)
+	}
+	if want := []llm.Example{{Source: "また来週", Target: "下周见"}}; !reflect.DeepEqual(inner.examples, want) {
+		t.Errorf("examples = %+v, want %+v", inner.examples, want)
+	}
+	if exported, err := store.All("ja", "zh"); err != nil || len(exported) != 2 {
+		t.Errorf("All() = %+v, %v", exported, err)
+	}
+}
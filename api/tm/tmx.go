@@ -0,0 +1,166 @@
+package tm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// tmxTime TMX 中 creationdate 的格式
+const tmxTime = "20060102T150405Z"
+
+type tmxDoc struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	SrcLang string `xml:"srclang,attr"`
+}
+
+type tmxUnit struct {
+	CreationDate string       `xml:"creationdate,attr"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxVariant struct {
+	// Lang 为 TMX 1.4 的 xml:lang，OldLang 为 TMX 1.1 的 lang
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	OldLang string `xml:"lang,attr"`
+	Seg     string `xml:"seg"`
+}
+
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.OldLang
+}
+
+// langMatch 判断 TMX 中的语言代码是否属于 want，如 ja-JP 属于 ja
+func langMatch(lang, want string) bool {
+	lang, want = strings.ToLower(lang), strings.ToLower(want)
+	return lang == want || strings.HasPrefix(lang, want+"-") || strings.HasPrefix(lang, want+"_")
+}
+
+// ReadTMX 读取 TMX 文件中 sourceLang 到 targetLang 的译文，语言代码按前缀匹配（ja 匹配 ja-JP）。
+// sourceLang 为空时使用 header 中的 srclang；targetLang 为空时导入所有其他语言。
+func ReadTMX(r io.Reader, sourceLang, targetLang string) ([]Unit, error) {
+	var doc tmxDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid tmx: %w", err)
+	}
+	if sourceLang == "" {
+		sourceLang = doc.Header.SrcLang
+	}
+	if sourceLang == "" || sourceLang == "*all*" {
+		return nil, fmt.Errorf("tmx header has no source language, please specify it")
+	}
+	var units []Unit
+	for _, tu := range doc.Units {
+		var source string
+		for _, v := range tu.Variants {
+			if langMatch(v.lang(), sourceLang) {
+				source = v.Seg
+				break
+			}
+		}
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+		created, _ := time.Parse(tmxTime, tu.CreationDate)
+		for _, v := range tu.Variants {
+			lang := v.lang()
+			if langMatch(lang, sourceLang) || strings.TrimSpace(v.Seg) == "" {
+				continue
+			}
+			if targetLang != "" {
+				if !langMatch(lang, targetLang) {
+					continue
+				}
+				lang = targetLang
+			}
+			units = append(units, Unit{
+				SourceLang: strings.ToLower(sourceLang),
+				TargetLang: strings.ToLower(lang),
+				Source:     source,
+				Target:     v.Seg,
+				Created:    created,
+			})
+		}
+	}
+	return units, nil
+}
+
+// 写入时直接使用 xml:lang 作为属性名，避免 encoding/xml 生成额外的命名空间声明
+type tmxOut struct {
+	XMLName xml.Name     `xml:"tmx"`
+	Version string       `xml:"version,attr"`
+	Header  tmxOutHeader `xml:"header"`
+	Units   []tmxOutUnit `xml:"body>tu"`
+}
+
+type tmxOutHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTmf                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxOutUnit struct {
+	CreationDate string          `xml:"creationdate,attr,omitempty"`
+	Variants     []tmxOutVariant `xml:"tuv"`
+}
+
+type tmxOutVariant struct {
+	Lang string `xml:"xml:lang,attr"`
+	Seg  string `xml:"seg"`
+}
+
+// WriteTMX 以 TMX 1.4 格式写出译文
+func WriteTMX(w io.Writer, units []Unit) error {
+	srcLang := "*all*"
+	for i, u := range units {
+		if i == 0 {
+			srcLang = u.SourceLang
+		} else if u.SourceLang != srcLang {
+			srcLang = "*all*"
+			break
+		}
+	}
+	doc := tmxOut{
+		Version: "1.4",
+		Header: tmxOutHeader{
+			CreationTool:        "srtt",
+			CreationToolVersion: "1",
+			SegType:             "block",
+			OTmf:                "srtt",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+		},
+	}
+	for _, u := range units {
+		tu := tmxOutUnit{Variants: []tmxOutVariant{{Lang: u.SourceLang, Seg: u.Source}, {Lang: u.TargetLang, Seg: u.Target}}}
+		if !u.Created.IsZero() {
+			tu.CreationDate = u.Created.UTC().Format(tmxTime)
+		}
+		doc.Units = append(doc.Units, tu)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -1,8 +1,29 @@
 package api
 
+import "context"
+
 type TranslateApi interface {
 	// Translate translates the given text from the source language to the target language.
 	// It returns the translated text as a string, or an error if the translation fails.
-	Translate(text []string, sourceLang string, targetLang string) ([]string, error)
+	// Implementations must stop in-flight requests when ctx is canceled or its deadline expires.
+	Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error)
 	Close() error
 }
+
+// Sequential is implemented by engines that keep conversational state between
+// requests (e.g. the chatgpt context queue) and therefore must not be called concurrently.
+type Sequential interface {
+	Sequential() bool
+}
+
+// IsSequential reports whether the engine requires its requests to be serialized.
+func IsSequential(c TranslateApi) bool {
+	s, ok := c.(Sequential)
+	return ok && s.Sequential()
+}
+
+// LanguageValidator is implemented by engines that can check a language pair
+// against the server before any text is sent.
+type LanguageValidator interface {
+	ValidateLanguages(ctx context.Context, sourceLang string, targetLang string) error
+}
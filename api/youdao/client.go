@@ -0,0 +1,117 @@
+package youdao
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUrl = "https://openapi.youdao.com/v2/api"
+	// maxChars 批量请求中所有 q 的长度总和上限
+	maxChars = 5000
+)
+
+type Client struct {
+	appKey  string
+	secret  string
+	httpCli *resty.Client
+	logger  *zap.SugaredLogger
+}
+
+func NewClient(appKey string, secret string, logger *zap.SugaredLogger, options ...func(*resty.Client)) *Client {
+	httpClient := resty.New()
+	httpClient.AddRetryCondition(func(r *resty.Response, err error) bool {
+		switch r.StatusCode() {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	})
+	for _, option := range options {
+		option(httpClient)
+	}
+	if httpClient.BaseURL == "" {
+		logger.Infof("No url provided, Using default url: %s", defaultUrl)
+		httpClient.SetBaseURL(defaultUrl)
+	}
+	return &Client{
+		appKey:  appKey,
+		secret:  secret,
+		httpCli: httpClient,
+		logger:  logger,
+	}
+}
+
+// Translate 使用批量翻译接口，每条字幕作为一个 q 参数
+func (c Client) Translate(ctx context.Context, t []string, sourceLang string, targetLang string) ([]string, error) {
+	salt := newSalt()
+	curtime := fmt.Sprint(time.Now().Unix())
+	form := url.Values{
+		"q":        t,
+		"from":     {lang(sourceLang)},
+		"to":       {lang(targetLang)},
+		"appKey":   {c.appKey},
+		"salt":     {salt},
+		"sign":     {sign(c.appKey, strings.Join(t, ""), salt, curtime, c.secret)},
+		"signType": {"v3"},
+		"curtime":  {curtime},
+	}
+	var result YoudaoResponse
+	resp, err := c.httpCli.R().
+		SetContext(ctx).
+		SetFormDataFromValues(form).
+		SetResult(&result).
+		Post("")
+	if err != nil {
+		c.logger.Errorw("Translation failed", zap.Error(err))
+		return nil, err
+	}
+	if resp.IsError() {
+		c.logger.Errorw("Translation failed", zap.String("status", resp.Status()))
+		return nil, api.HTTPError(resp.StatusCode(), resp.Header(), resp.Status())
+	}
+	if result.ErrorCode != "0" {
+		c.logger.Errorw("Translation failed", zap.String("error_code", result.ErrorCode))
+		return nil, api.NewError(errorKinds[result.ErrorCode], result.ErrorCode, "")
+	}
+	if len(result.ErrorIndex) > 0 || len(result.TranslateResults) != len(t) {
+		return nil, fmt.Errorf("Translation failed: got %d translations for %d texts, failed %v", len(result.TranslateResults), len(t), result.ErrorIndex)
+	}
+	translatedText := make([]string, len(result.TranslateResults))
+	for i, r := range result.TranslateResults {
+		translatedText[i] = r.Translation
+	}
+	return translatedText, nil
+}
+
+func newSalt() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func lang(code string) string {
+	if v, ok := langMap[strings.ToLower(code)]; ok {
+		return v
+	}
+	return code
+}
+
+// Limits 每条字幕是一个 q 参数
+func (c *Client) Limits() api.Limits {
+	return api.Limits{MaxChars: maxChars}
+}
+
+func (c *Client) Close() error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package youdao
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+// newTestServer 模拟有道批量翻译接口：校验签名，译文为 "<to>:" + 原文
+func newTestServer(t *testing.T, errorCode string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		f := r.PostForm
+		q := f["q"]
+		if f.Get("signType") != "v3" || f.Get("sign") != sign(f.Get("appKey"), strings.Join(q, ""), f.Get("salt"), f.Get("curtime"), "secret") {
+			t.Errorf("invalid sign")
+		}
+		resp := YoudaoResponse{ErrorCode: errorCode}
+		for _, text := range q {
+			resp.TranslateResults = append(resp.TranslateResults, struct {
+				Query       string `json:"query"`
+				Translation string `json:"translation"`
+				Type        string `json:"type"`
+			}{Query: text, Translation: f.Get("to") + ":" + text})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestClient_Translate(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	tests := []struct {
+		name      string
+		errorCode string
+		want      []string
+		wantErr   bool
+		wantKind  api.ErrorKind
+	}{
+		{name: "batch", errorCode: "0", want: []string{"zh-CHS:何恥ずかしがってんだ、白って言え。\n二行目", "zh-CHS:三行目"}},
+		{name: "error code", errorCode: "202", wantErr: true, wantKind: api.KindAuth},
+		{name: "rate limited", errorCode: "411", wantErr: true, wantKind: api.KindRateLimited},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.errorCode)
+			defer server.Close()
+			c := NewClient("appkey", "secret", logger, WithBaseUrl(server.URL))
+			got, err := c.Translate(context.Background(), []string{"何恥ずかしがってんだ、白って言え。\n二行目", "三行目"}, "ja", "zh")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if kind := api.KindOf(err); kind != tt.wantKind {
+				t.Errorf("Translate() error kind = %v, want %v", kind, tt.wantKind)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Translate() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package youdao
+
+import (
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"go.uber.org/zap"
+)
+
+func init() {
+	api.Register(api.Engine{
+		Name:        "youdao",
+		Description: "Youdao AI open platform batch translation (openapi.youdao.com)",
+		Settings: append([]api.Setting{
+			{Name: "app_key", Type: api.StringSetting, Required: true, Flag: "apiKey", Description: "Application ID from the Youdao console"},
+			{Name: "secret", Type: api.StringSetting, Required: true, Flag: "apiSecret", Description: "Application secret from the Youdao console"},
+		}, api.HTTPSettings(defaultUrl)...),
+		New: func(cfg api.Config, logger *zap.SugaredLogger) (api.TranslateApi, error) {
+			return NewClient(cfg.String("app_key"), cfg.String("secret"), logger,
+				WithBaseUrl(cfg.String("url")),
+				WithDebug(cfg.Bool("debug")),
+				WithRetry(cfg.Int("retry")),
+				WithRetryWaitTime(time.Duration(cfg.Int("retry_wait"))*time.Millisecond),
+			), nil
+		},
+	})
+}
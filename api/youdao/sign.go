@@ -0,0 +1,24 @@
+package youdao
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// 签名方法 v3：
+// sign = sha256(应用ID + input + salt + curtime + 应用密钥)
+// 其中 input 的计算方式为：input = q 前10个字符 + q 长度 + q 后10个字符（当 q 长度大于20）或 input = q（当 q 长度小于等于20）。
+// 批量翻译时 q 为所有待翻译文本按顺序拼接的字符串。
+// 注：长度按字符（而非字节）计算。
+func sign(appKey string, q string, salt string, curtime string, secret string) string {
+	s1 := appKey + truncate(q) + salt + curtime + secret
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s1)))
+}
+
+func truncate(q string) string {
+	r := []rune(q)
+	if len(r) <= 20 {
+		return q
+	}
+	return fmt.Sprintf("%s%d%s", string(r[:10]), len(r), string(r[len(r)-10:]))
+}
@@ -0,0 +1,61 @@
+package youdao
+
+import "testing"
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want string
+	}{
+		{name: "short", q: "hello", want: "hello"},
+		{name: "exactly 20", q: "abcdefghijklmnopqrst", want: "abcdefghijklmnopqrst"},
+		{name: "long ascii", q: "abcdefghijklmnopqrstu", want: "abcdefghij21lmnopqrstu"},
+		{name: "counts characters not bytes", q: "何恥ずかしがってんだ、白って言え。そう言われても困るんだけど", want: "何恥ずかしがってんだ30われても困るんだけど"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncate(tt.q); got != tt.want {
+				t.Errorf("truncate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_sign 按有道智云文本翻译文档的签名规则：sign = sha256(appKey + input + salt + curtime + appSecret)，
+// q 超过 20 个字符时 input 为前 10 个字符 + 字符数 + 后 10 个字符。文档没有给出完整的签名示例，
+// 期望值由 printf '%s' '<拼接串>' | sha256sum 独立计算，拼接串见各用例注释
+func Test_sign(t *testing.T) {
+	type args struct {
+		appKey  string
+		q       string
+		salt    string
+		curtime string
+		secret  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			// appkeyhellosalt1700000000secret
+			name: "short input",
+			args: args{appKey: "appkey", q: "hello", salt: "salt", curtime: "1700000000", secret: "secret"},
+			want: "410d75111b6c4ce44e3293b69c0f6f84d905f5c1a5dc8fbdd8cc143672523f42",
+		},
+		{
+			// appkey何恥ずかしがってんだ30われても困るんだけどsalt1700000000secret
+			name: "truncated input",
+			args: args{appKey: "appkey", q: "何恥ずかしがってんだ、白って言え。そう言われても困るんだけど", salt: "salt", curtime: "1700000000", secret: "secret"},
+			want: "e8ccae5f72f854152e4597a31f88b2f9bf2cee05efa44d29af2620413b38ca63",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sign(tt.args.appKey, tt.args.q, tt.args.salt, tt.args.curtime, tt.args.secret); got != tt.want {
+				t.Errorf("sign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package youdao
+
+import "github.com/AnTengye/srtt/api"
+
+// 批量翻译 POST https://openapi.youdao.com/v2/api，Content-Type 为 application/x-www-form-urlencoded
+//
+// 字段名	类型	是否必填	描述
+// q	text	是	待翻译文本，可传多个
+// from	text	是	源语言，可设置为 auto
+// to	text	是	目标语言
+// appKey	text	是	应用ID
+// salt	text	是	UUID
+// sign	text	是	签名，sha256(应用ID+input+salt+curtime+应用密钥)
+// signType	text	是	签名类型，v3
+// curtime	text	是	当前 UTC 时间戳（秒）
+type YoudaoResponse struct {
+	ErrorCode        string `json:"errorCode"`
+	ErrorIndex       []int  `json:"errorIndex"`
+	TranslateResults []struct {
+		Query       string `json:"query"`
+		Translation string `json:"translation"`
+		Type        string `json:"type"`
+	} `json:"translateResults"`
+}
+
+// 自动识别	auto	中文	zh-CHS	中文繁体	zh-CHT
+// 英文	en	日文	ja	韩文	ko
+// 法文	fr	西班牙文	es	葡萄牙文	pt
+// 意大利文	it	俄文	ru	越南文	vi
+// 德文	de	阿拉伯文	ar	印尼文	id
+// 泰文	th
+var langMap = map[string]string{
+	"auto":  "auto",
+	"zh":    "zh-CHS",
+	"zh-cn": "zh-CHS",
+	"zh-tw": "zh-CHT",
+	"cht":   "zh-CHT",
+	"en":    "en",
+	"ja":    "ja",
+	"jp":    "ja",
+	"ko":    "ko",
+	"kor":   "ko",
+	"fr":    "fr",
+	"fra":   "fr",
+	"es":    "es",
+	"spa":   "es",
+	"pt":    "pt",
+	"it":    "it",
+	"ru":    "ru",
+	"vi":    "vi",
+	"vie":   "vi",
+	"de":    "de",
+	"ar":    "ar",
+	"ara":   "ar",
+	"id":    "id",
+	"th":    "th",
+}
+
+// errorKinds 有道错误码对应的错误类型
+// 101 缺少必填参数	102 不支持的语言类型	103 翻译文本过长	108 应用ID无效
+// 110 无相关服务的有效应用	111 开发者账号无效	202 签名检验失败	206 时间戳无效
+// 302 翻译查询失败	303 服务端的其它异常	401 账户已经欠费	411 访问频率受限	412 长请求过于频繁
+var errorKinds = map[string]api.ErrorKind{
+	"102": api.KindInvalidLanguage,
+	"103": api.KindTooLong,
+	"108": api.KindAuth,
+	"110": api.KindAuth,
+	"111": api.KindAuth,
+	"202": api.KindAuth,
+	"206": api.KindAuth,
+	"401": api.KindQuota,
+	"411": api.KindRateLimited,
+	"412": api.KindRateLimited,
+	"302": api.KindTransient,
+	"303": api.KindTransient,
+}
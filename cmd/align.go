@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/fallback"
+)
+
+// errUnaligned 拆分到单条字幕后译文条数仍然不一致
+var errUnaligned = errors.New("译文无法与字幕对齐")
+
+// alignReport 记录需要拆分才能对齐以及最终无法对齐的字幕序号，以及每条译文由哪个引擎完成
+type alignReport struct {
+	mu      sync.Mutex
+	split   []int
+	failed  []int
+	engines map[int]string
+}
+
+func (r *alignReport) add(list *[]int, start, end int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := start; i < end; i++ {
+		*list = append(*list, i)
+	}
+}
+
+func (r *alignReport) produced(start, end int, engine string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.engines == nil {
+		r.engines = map[int]string{}
+	}
+	for i := start; i < end; i++ {
+		r.engines[i] = engine
+	}
+}
+
+// Engines 按引擎汇总译文对应的字幕序号，未经过引擎链的（如命中翻译记忆）引擎名为空
+func (r *alignReport) Engines() map[string][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cues := map[string][]int{}
+	for i, e := range r.engines {
+		cues[e] = append(cues[e], i)
+	}
+	for _, idx := range cues {
+		sort.Ints(idx)
+	}
+	return cues
+}
+
+func (r *alignReport) isFailed(i int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Contains(r.failed, i)
+}
+
+// Split 经过拆分重新翻译才对齐的字幕
+func (r *alignReport) Split() []int {
+	return r.sorted(r.split)
+}
+
+// Failed 无法对齐、未采用译文的字幕
+func (r *alignReport) Failed() []int {
+	return r.sorted(r.failed)
+}
+
+func (r *alignReport) sorted(list []int) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idx := append([]int(nil), list...)
+	sort.Ints(idx)
+	return idx
+}
+
+// 引擎限流时的重试次数与等待时间，引擎未给出 Retry-After 时按 rateLimitWait 指数退避
+var (
+	rateLimitRetries = 5
+	rateLimitWait    = time.Second
+	maxRateLimitWait = 30 * time.Second
+)
+
+// translateBackoff 调用引擎翻译，遇到限流时等待后重试
+func translateBackoff(ctx context.Context, client api.TranslateApi, text []string) ([]string, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := client.Translate(ctx, text, sourceLang, targetLang)
+		if api.KindOf(err) != api.KindRateLimited || attempt >= rateLimitRetries {
+			return result, err
+		}
+		wait := api.RetryAfter(err)
+		if wait <= 0 {
+			wait = rateLimitWait << attempt
+		}
+		if wait > maxRateLimitWait {
+			wait = maxRateLimitWait
+		}
+		logger.Warnf("引擎限流，%s 后重试（第%d次）", wait, attempt+1)
+		if !sleep(ctx, wait) {
+			return nil, err
+		}
+	}
+}
+
+// checkAlignment 校验译文条数与原文一致
+func checkAlignment(text, result []string) error {
+	if len(result) != len(text) {
+		return fmt.Errorf("返回 %d 条译文，应为 %d 条", len(result), len(text))
+	}
+	return nil
+}
+
+// translateAligned 翻译 text（对应第 offset 条起的字幕）并校验条数。
+// 条数不一致时对半拆分后分别重新翻译，直到单条字幕；前 skip 条只作为上下文，拆分后不再单独翻译。
+// 引擎报告文本过长时同样拆分，单条字幕仍然过长则跳过该行。
+// 返回值与 text 等长，无法对齐的字幕为空字符串，并记录到 report；strictAlign 时返回 errUnaligned。
+func translateAligned(ctx context.Context, client api.TranslateApi, text []string, offset, skip int, split bool, report *alignReport) ([]string, error) {
+	trackCtx, used := fallback.Track(ctx)
+	result, err := translateBackoff(trackCtx, client, text)
+	tooLong := api.KindOf(err) == api.KindTooLong
+	if err != nil && !tooLong {
+		return nil, err
+	}
+	if err == nil {
+		err = checkAlignment(text, result)
+	}
+	if err == nil {
+		if split {
+			report.add(&report.split, offset+skip, offset+len(text))
+		}
+		report.produced(offset+skip, offset+len(text), used.Engine())
+		return result, nil
+	}
+	if len(text) == 1 {
+		report.add(&report.failed, offset, offset+1)
+		if tooLong {
+			logger.Errorf("第%d行翻译失败: %s，已跳过", offset+1, err)
+			return []string{""}, nil
+		}
+		logger.Errorf("第%d行%s，%s", offset+1, err, errUnaligned)
+		if strictAlign {
+			return nil, fmt.Errorf("第%d行: %w", offset+1, errUnaligned)
+		}
+		return []string{""}, nil
+	}
+	logger.Warnf("第%d-%d行%s，拆分后重新翻译", offset+1, offset+len(text), err)
+	mid := len(text) / 2
+	aligned := make([]string, len(text))
+	if skip < mid {
+		left, err := translateAligned(ctx, client, text[:mid], offset, skip, true, report)
+		if err != nil {
+			return nil, err
+		}
+		copy(aligned, left)
+	}
+	rightSkip := skip - mid
+	if rightSkip < 0 {
+		rightSkip = 0
+	}
+	right, err := translateAligned(ctx, client, text[mid:], offset+mid, rightSkip, true, report)
+	if err != nil {
+		return nil, err
+	}
+	copy(aligned[mid:], right)
+	return aligned, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/azure"
+	"github.com/spf13/cobra"
+)
+
+var lookupFrom, lookupTo string
+
+// azureRegion azure 引擎的 region 设置，translate 和 azure lookup 共用
+var azureRegion string
+
+// azureCmd represents the azure command
+var azureCmd = &cobra.Command{
+	Use:   "azure",
+	Short: "Azure Translator helpers",
+}
+
+var azureLookupCmd = &cobra.Command{
+	Use:   "lookup <word>",
+	Short: "look up alternative translations of a word in the Azure dictionary",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		e, _ := api.Lookup("azure")
+		cfg, err := e.Config(engineValues(cmd, e))
+		if err != nil {
+			logger.Fatal(err)
+		}
+		client := azure.NewClient(cfg.String("key"), cfg.String("region"), logger,
+			azure.WithBaseUrl(cfg.String("url")), azure.WithDebug(cfg.Bool("debug")))
+		translations, err := client.Lookup(cmd.Context(), args[0], lookupFrom, lookupTo)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, t := range translations {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\n", t.DisplayTarget, t.PosTag, t.Confidence)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(azureCmd)
+	azureCmd.AddCommand(azureLookupCmd)
+	azureLookupCmd.Flags().StringVarP(&lookupFrom, "source", "s", "en", "Source language")
+	azureLookupCmd.Flags().StringVarP(&lookupTo, "target", "t", "zh", "Target language")
+	azureLookupCmd.Flags().StringVarP(&azureRegion, "azure-region", "", "", "Azure Translator resource region")
+	azureLookupCmd.Flags().StringToStringVarP(&engineConfig, "engine-config", "", nil, "Engine settings as name=value, e.g. key=xxx,region=eastasia")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AnTengye/srtt/api/cache"
+	"github.com/spf13/cobra"
+)
+
+var pruneOlderThan time.Duration
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage translation cache",
+	Long: `
+manage the translation cache stored in --cache-dir
+`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "show cache statistics",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := openCache()
+		defer store.Close()
+		st, err := store.Stats()
+		if err != nil {
+			logger.Fatal(err)
+		}
+		fmt.Printf("path:    %s\n", st.Path)
+		fmt.Printf("size:    %d bytes\n", st.Size)
+		fmt.Printf("entries: %d\n", st.Total)
+		if st.Total > 0 {
+			fmt.Printf("oldest:  %s\n", st.Oldest.Format(time.DateTime))
+			fmt.Printf("newest:  %s\n", st.Newest.Format(time.DateTime))
+		}
+		engines := make([]string, 0, len(st.Engines))
+		for e := range st.Engines {
+			engines = append(engines, e)
+		}
+		sort.Strings(engines)
+		for _, e := range engines {
+			fmt.Printf("  %-10s %d\n", e, st.Engines[e])
+		}
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "remove cache entries older than --older-than",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := openCache()
+		defer store.Close()
+		n, err := store.Prune(pruneOlderThan)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		logger.Infof("已删除 %d 条缓存", n)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "remove all cache entries",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := openCache()
+		defer store.Close()
+		if err := store.Clear(); err != nil {
+			logger.Fatal(err)
+		}
+		logger.Infof("缓存已清空")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd, cachePruneCmd, cacheClearCmd)
+
+	cachePruneCmd.Flags().DurationVarP(&pruneOlderThan, "older-than", "", 30*24*time.Hour, "Remove entries older than this duration")
+}
+
+func openCache() *cache.Store {
+	store, err := cache.Open(cacheDir)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	return store
+}
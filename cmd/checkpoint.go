@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// checkpoint 翻译进度，每完成一块写入输出文件旁的 sidecar 文件，用于 --resume 续翻。
+// Translated 中有记录的字幕即已完成，译文可以为空（如原文为空或引擎返回空译文）
+type checkpoint struct {
+	path string
+	mu   sync.Mutex
+
+	Hash       string         `json:"hash"`
+	Engine     string         `json:"engine"`
+	SourceLang string         `json:"source_lang"`
+	TargetLang string         `json:"target_lang"`
+	Total      int            `json:"total"`
+	Translated map[int]string `json:"translated"`
+}
+
+func checkpointPath(outputPath string) string {
+	return outputPath + ".checkpoint.json"
+}
+
+func textsHash(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func newCheckpoint(path string, lines []string) *checkpoint {
+	cp := &checkpoint{
+		path:       path,
+		Hash:       textsHash(lines),
+		Engine:     engine,
+		SourceLang: sourceLang,
+		TargetLang: targetLang,
+		Total:      len(lines),
+		Translated: map[int]string{},
+	}
+	cp.skipEmpty(lines)
+	return cp
+}
+
+// skipEmpty 原文为空的字幕无需翻译，直接记为完成
+func (cp *checkpoint) skipEmpty(lines []string) {
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			cp.Translated[i] = ""
+		}
+	}
+}
+
+// loadCheckpoint 读取进度文件并确认与本次任务一致
+func loadCheckpoint(path string, lines []string) (*checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cp := &checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	cp.path = path
+	want := newCheckpoint(path, lines)
+	switch {
+	case cp.Hash != want.Hash || cp.Total != want.Total:
+		return nil, errors.New("checkpoint does not match the input file")
+	case cp.Engine != want.Engine || cp.SourceLang != want.SourceLang || cp.TargetLang != want.TargetLang:
+		return nil, fmt.Errorf("checkpoint was created by engine %s (%s -> %s)", cp.Engine, cp.SourceLang, cp.TargetLang)
+	}
+	if cp.Translated == nil {
+		cp.Translated = map[int]string{}
+	}
+	cp.skipEmpty(lines)
+	return cp, nil
+}
+
+// done 第 i 条字幕是否已翻译
+func (cp *checkpoint) done(i int) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	_, ok := cp.Translated[i]
+	return ok
+}
+
+func (cp *checkpoint) set(i int, text string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.Translated[i] = text
+}
+
+// missing 返回尚未翻译的字幕序号
+func (cp *checkpoint) missing() []int {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	var idx []int
+	for i := 0; i < cp.Total; i++ {
+		if _, ok := cp.Translated[i]; !ok {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// save 先写临时文件再重命名，避免中断时损坏进度文件
+func (cp *checkpoint) save() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := cp.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cp.path)
+}
+
+func (cp *checkpoint) remove() error {
+	err := os.Remove(cp.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/AnTengye/srtt/subtitle"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertInput  string
+	convertOutput string
+	convertFrom   string
+	convertTo     string
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "convert subtitle format",
+	Long: `
+convert subtitle file between srt, vtt, ass and ssa without translating.
+
+Some conversions are lossy and print a warning, for example:
+  ass -> srt/vtt: script info, styles, Comment lines and override tags are dropped
+  vtt -> srt/ass: NOTE/STYLE/REGION blocks, cue identifiers and cue settings are dropped
+  srt -> vtt/ass: X1/X2/Y1/Y2 coordinates are dropped
+`,
+	Run: convertRun,
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().StringVarP(&convertInput, "input", "i", "", "Input file path")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "Output file path")
+	convertCmd.Flags().StringVarP(&convertFrom, "from", "", "", "Input format: srt, vtt, ass, ssa. Detected from input extension by default")
+	convertCmd.Flags().StringVarP(&convertTo, "to", "", "", "Output format: srt, vtt, ass, ssa. Detected from output extension by default")
+	_ = convertCmd.MarkFlagRequired("input")
+	_ = convertCmd.MarkFlagRequired("output")
+}
+
+func convertRun(cmd *cobra.Command, args []string) {
+	from, err := resolveFormat(convertFrom, convertInput)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	to, err := resolveFormat(convertTo, convertOutput)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	doc, err := readSubtitle(convertInput, from)
+	if err != nil {
+		logger.Fatalf("字幕解析失败: %s", err)
+	}
+	out, warnings := subtitle.Convert(doc, to)
+	for _, w := range warnings {
+		logger.Warnf("%s -> %s: %s", from, to, w)
+	}
+	if err := writeSubtitle(convertOutput, out); err != nil {
+		logger.Fatal(err)
+	}
+	logger.Infof("转换完成, 结果已保存到 %s", convertOutput)
+}
+
+// resolveFormat 优先使用指定的格式，否则根据扩展名判断
+func resolveFormat(name, path string) (subtitle.Format, error) {
+	if name != "" {
+		return subtitle.ParseFormat(name)
+	}
+	return subtitle.DetectFormat(path)
+}
+
+func readSubtitle(path string, format subtitle.Format) (*subtitle.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return subtitle.Parse(file, format)
+}
+
+func writeSubtitle(path string, doc *subtitle.Document) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := doc.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
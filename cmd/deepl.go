@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/deepl"
+	"github.com/spf13/cobra"
+)
+
+// deeplCmd represents the deepl command
+var deeplCmd = &cobra.Command{
+	Use:   "deepl",
+	Short: "official DeepL API helpers",
+}
+
+var deeplUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "show the character quota of the DeepL account",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		e, _ := api.Lookup("deepl")
+		cfg, err := e.Config(engineValues(cmd, e))
+		if err != nil {
+			logger.Fatal(err)
+		}
+		client := deepl.NewClient(cfg.String("api_key"), logger, deepl.WithBaseUrl(cfg.String("url")), deepl.WithDebug(cfg.Bool("debug")))
+		usage, err := client.Usage(cmd.Context())
+		if err != nil {
+			logger.Fatal(err)
+		}
+		fmt.Printf("used:      %d characters\n", usage.CharacterCount)
+		fmt.Printf("limit:     %d characters\n", usage.CharacterLimit)
+		if usage.CharacterLimit > 0 {
+			fmt.Printf("remaining: %d characters (%.1f%% used)\n", usage.CharacterLimit-usage.CharacterCount,
+				float64(usage.CharacterCount)*100/float64(usage.CharacterLimit))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deeplCmd)
+	deeplCmd.AddCommand(deeplUsageCmd)
+	deeplUsageCmd.Flags().StringToStringVarP(&engineConfig, "engine-config", "", nil, "Engine settings as name=value, e.g. api_key=xxx")
+}
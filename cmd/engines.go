@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AnTengye/srtt/api"
+	_ "github.com/AnTengye/srtt/api/anthropic"
+	_ "github.com/AnTengye/srtt/api/azure"
+	_ "github.com/AnTengye/srtt/api/baidu"
+	_ "github.com/AnTengye/srtt/api/chatgpt"
+	_ "github.com/AnTengye/srtt/api/deepl"
+	_ "github.com/AnTengye/srtt/api/deeplx"
+	_ "github.com/AnTengye/srtt/api/google"
+	_ "github.com/AnTengye/srtt/api/libretranslate"
+	_ "github.com/AnTengye/srtt/api/ollama"
+	_ "github.com/AnTengye/srtt/api/tencent"
+	_ "github.com/AnTengye/srtt/api/youdao"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// enginesCmd represents the engines command
+var enginesCmd = &cobra.Command{
+	Use:   "engines",
+	Short: "translation engines",
+}
+
+var enginesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list available engines and their settings",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, e := range api.Engines() {
+			fmt.Fprintf(w, "%s\t%s\n", e.Name, e.Description)
+			for _, s := range e.Settings {
+				attr := "optional"
+				if s.Required {
+					attr = "required"
+				} else if s.Default != "" {
+					attr = "default " + s.Default
+				}
+				desc := s.Description
+				if s.Flag != "" {
+					desc += fmt.Sprintf(" (--%s)", s.Flag)
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", s.Name, s.Type, attr, desc)
+			}
+			fmt.Fprintln(w)
+		}
+		w.Flush()
+		fmt.Println(`Settings are read, in order of precedence, from:
+  --engine-config name=value
+  the flag shown in parentheses (shared legacy flags only apply to the first engine)
+  config file key engines.<engine>.<name>
+  environment variable SRTT_ENGINES_<ENGINE>_<NAME>`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(enginesCmd)
+	enginesCmd.AddCommand(enginesListCmd)
+}
+
+func engineNames() []string {
+	var names []string
+	for _, e := range api.Engines() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// engineValues 汇总引擎配置：配置文件/环境变量 < 旧参数 < --engine-config
+func engineValues(cmd *cobra.Command, e api.Engine) map[string]string {
+	return mergeEngineValues(cmd, e, true, false)
+}
+
+// mergeEngineValues legacyFlags 为 false 时忽略旧参数；chain 为 true 时不带引擎名的 --engine-config
+// 只作用于具有该设置的引擎。"引擎名.name=value" 形式的设置始终只作用于对应引擎
+func mergeEngineValues(cmd *cobra.Command, e api.Engine, legacyFlags bool, chain bool) map[string]string {
+	values := map[string]string{}
+	for _, s := range e.Settings {
+		if v := viper.GetString(strings.Join([]string{"engines", e.Name, s.Name}, ".")); v != "" {
+			values[s.Name] = v
+		}
+		// 以引擎名开头的参数只属于该引擎，链中任何位置都生效
+		if s.Flag == "" || (!legacyFlags && !strings.HasPrefix(s.Flag, e.Name+"-")) {
+			continue
+		}
+		if f := cmd.Flags().Lookup(s.Flag); f != nil && f.Changed {
+			values[s.Name] = f.Value.String()
+		}
+	}
+	for k, v := range engineConfig {
+		if name, key, ok := strings.Cut(k, "."); ok {
+			if name == e.Name {
+				values[key] = v
+			}
+			continue
+		}
+		if _, ok := e.Setting(k); chain && !ok {
+			continue
+		}
+		values[k] = v
+	}
+	return values
+}
+
+// checkChainConfig 检查 --engine-config 中的每个设置都至少属于链中的一个引擎
+func checkChainConfig(engines []api.Engine) error {
+	for k := range engineConfig {
+		name, key, scoped := strings.Cut(k, ".")
+		found := false
+		for _, e := range engines {
+			if scoped && e.Name != name {
+				continue
+			}
+			if !scoped {
+				key = k
+			}
+			if _, ok := e.Setting(key); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no engine in %s has setting %q", engine, k)
+		}
+	}
+	return nil
+}
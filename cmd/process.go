@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"golang.org/x/time/rate"
+)
+
+// defaultBlockSize 未指定 --processLength 且引擎没有请求大小限制时每块的字幕条数
+const defaultBlockSize = 10
+
+// block 一次请求翻译的字幕区间 [start, end)，只有 [start+from, end) 的结果会被采用，
+// 之前的 from 条字幕仅作为上下文
+type block struct {
+	start, end, from int
+}
+
+// splitBlocks 把字幕装入尽量大的块：每块最多 blockSize 条，并满足引擎的请求大小限制 limits。
+// 相邻块重叠 overlap 条作为上下文，上下文同样计入限制，放不下时减少上下文；单条字幕超出限制时单独成块。
+func splitBlocks(lines []string, blockSize, overlap int, limits api.Limits) []block {
+	if blockSize <= 0 || (limits.MaxSegments > 0 && limits.MaxSegments < blockSize) {
+		blockSize = limits.MaxSegments
+	}
+	// sizes[i] 为前 i 条字幕的大小之和
+	sizes := make([]api.Usage, len(lines)+1)
+	for i, line := range lines {
+		sizes[i+1] = sizes[i].Add(limits.Measure(line))
+	}
+	fits := func(start, end int) bool {
+		return (blockSize <= 0 || end-start <= blockSize) && limits.Allows(sizes[end].Sub(sizes[start]))
+	}
+	var blocks []block
+	for i := 0; i < len(lines); {
+		start := i - overlap
+		if start < 0 {
+			start = 0
+		}
+		for start < i && !fits(start, i+1) {
+			start++
+		}
+		end := i + 1
+		for end < len(lines) && fits(start, end+1) {
+			end++
+		}
+		blocks = append(blocks, block{start: start, end: end, from: i - start})
+		i = end
+	}
+	return blocks
+}
+
+// processText 分块翻译 lines，返回译文和对齐情况；strictAlign 时遇到无法对齐的字幕，
+// 或引擎返回认证失败、额度用尽等无法重试的错误时会停止并返回错误
+func processText(ctx context.Context, client api.TranslateApi, lines []string, blockSize, overlap, workers int, cp *checkpoint) ([]string, *alignReport, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	report := &alignReport{}
+	translatedText := make([]string, len(lines))
+	for i, t := range cp.Translated {
+		if i >= 0 && i < len(lines) {
+			translatedText[i] = t
+		}
+	}
+	var throttler *rate.Limiter
+	if coffeeLength != 0 && coffeeTime != 0 {
+		throttler = rate.NewLimiter(
+			rate.Every(time.Duration(coffeeTime)*time.Second),
+			coffeeLength,
+		)
+	}
+	limits := api.LimitsOf(client)
+	if blockSize <= 0 && limits.IsZero() {
+		blockSize = defaultBlockSize
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > 1 && api.IsSequential(client) {
+		logger.Infof("当前引擎依赖上下文顺序，忽略 --workers=%d", workers)
+		workers = 1
+	}
+
+	jobs := make(chan block)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				if throttler != nil {
+					reserve := throttler.Reserve()
+					if reserve.Delay() != 0 {
+						logger.Infof("已翻译%d行，喝杯咖啡需花费%d秒", b.start, int(reserve.Delay().Seconds()))
+						if !sleep(ctx, reserve.Delay()) {
+							reserve.Cancel()
+							continue
+						}
+					}
+				}
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := translateBlock(ctx, client, lines, b, translatedText, cp, report); stopsTranslation(err) {
+					cancel(err)
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, b := range splitBlocks(lines, blockSize, overlap, limits) {
+		if blockDone(cp, b.start+b.from, b.end) {
+			continue
+		}
+		select {
+		case jobs <- b:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := context.Cause(ctx); stopsTranslation(err) {
+		return translatedText, report, err
+	}
+	return translatedText, report, nil
+}
+
+// stopsTranslation 错误是否应停止整个翻译，而不是只让当前块失败后继续
+func stopsTranslation(err error) bool {
+	return errors.Is(err, errUnaligned) || api.IsPermanent(err)
+}
+
+// translateBlock 翻译一个区间，按字幕序号写回结果，各块写入的区间互不重叠
+func translateBlock(ctx context.Context, client api.TranslateApi, lines []string, b block, translatedText []string, cp *checkpoint, report *alignReport) error {
+	// 提取当前窗口的行
+	currentBlock := lines[b.start:b.end]
+	logger.Infof("正在翻译第%d-%d行", b.start+1, b.end)
+	logger.Debugf("原文：\n %s", strings.Join(currentBlock, "\n----\n"))
+	// 调用处理函数
+	if requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+	result, err := translateAligned(ctx, client, currentBlock, b.start, b.from, false, report)
+	if err != nil {
+		logger.Errorf("第%d-%d行翻译失败: %s", b.start+1, b.end, err)
+		return err
+	}
+	logger.Debugf("译文：\n%s", result)
+	for j := b.from; j < len(currentBlock) && j < len(result); j++ {
+		i := b.start + j
+		if cp.done(i) {
+			continue
+		}
+		translatedText[i] = strings.TrimSpace(result[j])
+		if !report.isFailed(i) {
+			cp.set(i, translatedText[i])
+		}
+	}
+	if err := cp.save(); err != nil {
+		logger.Warnf("保存进度失败: %s", err)
+	}
+	return nil
+}
+
+// sleep 等待 d，ctx 结束时提前返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// blockDone 区间 [start, end) 内的字幕是否都已翻译
+func blockDone(cp *checkpoint, start, end int) bool {
+	for i := start; i < end; i++ {
+		if !cp.done(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatCueRanges 将字幕序号格式化为 "1-3, 7"（从 1 开始）
+func formatCueRanges(idx []int) string {
+	var parts []string
+	for k := 0; k < len(idx); {
+		start := k
+		for k+1 < len(idx) && idx[k+1] == idx[k]+1 {
+			k++
+		}
+		if start == k {
+			parts = append(parts, fmt.Sprintf("%d", idx[k]+1))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", idx[start]+1, idx[k]+1))
+		}
+		k++
+	}
+	return strings.Join(parts, ", ")
+}
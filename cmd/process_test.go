@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnTengye/srtt/api"
+	"github.com/AnTengye/srtt/api/fallback"
+)
+
+type upperEngine struct {
+	sequential bool
+}
+
+func (e *upperEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	result := make([]string, len(text))
+	for i, t := range text {
+		result[i] = strings.ToUpper(t)
+	}
+	return result, nil
+}
+
+func (e *upperEngine) Sequential() bool {
+	return e.sequential
+}
+
+func (e *upperEngine) Close() error {
+	return nil
+}
+
+func Test_splitBlocks(t *testing.T) {
+	tests := []struct {
+		name                      string
+		total, blockSize, overlap int
+		limits                    api.Limits
+		want                      []block
+	}{
+		{name: "no overlap", total: 5, blockSize: 2, overlap: 0, want: []block{{0, 2, 0}, {2, 4, 0}, {4, 5, 0}}},
+		{name: "overlap", total: 10, blockSize: 5, overlap: 2, want: []block{{0, 5, 0}, {3, 8, 2}, {6, 10, 2}}},
+		{name: "single block", total: 10, blockSize: 10, overlap: 3, want: []block{{0, 10, 0}}},
+		{name: "max segments", total: 5, blockSize: 10, limits: api.Limits{MaxSegments: 2}, want: []block{{0, 2, 0}, {2, 4, 0}, {4, 5, 0}}},
+		// 每条字幕 4 字节，分隔符 1 字节：3 条为 14 字节
+		{name: "max bytes", total: 6, blockSize: 10, limits: api.Limits{MaxBytes: 14, Separator: "\n"}, want: []block{{0, 3, 0}, {3, 6, 0}}},
+		{name: "context counts", total: 6, blockSize: 10, overlap: 1, limits: api.Limits{MaxBytes: 14, Separator: "\n"}, want: []block{{0, 3, 0}, {2, 5, 1}, {4, 6, 1}}},
+		{name: "context dropped", total: 3, blockSize: 10, overlap: 1, limits: api.Limits{MaxChars: 4}, want: []block{{0, 1, 0}, {1, 2, 0}, {2, 3, 0}}},
+		{name: "max tokens", total: 4, blockSize: 10, limits: api.Limits{MaxTokens: 2, Tokenizer: func(string) int { return 1 }}, want: []block{{0, 2, 0}, {2, 4, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make([]string, tt.total)
+			for i := range lines {
+				lines[i] = "line"
+			}
+			if got := splitBlocks(lines, tt.blockSize, tt.overlap, tt.limits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitBlocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_processText(t *testing.T) {
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = "line" + strings.Repeat("x", i%3)
+	}
+	for _, workers := range []int{1, 4} {
+		for _, sequential := range []bool{false, true} {
+			client := &upperEngine{sequential: sequential}
+			cp := newCheckpoint(filepath.Join(t.TempDir(), "cp.json"), lines)
+			got, _, err := processText(context.Background(), client, lines, 10, 3, workers, cp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range lines {
+				if got[i] != strings.ToUpper(lines[i]) {
+					t.Fatalf("workers=%d: cue %d = %q, want %q", workers, i, got[i], strings.ToUpper(lines[i]))
+				}
+			}
+			if len(cp.missing()) != 0 {
+				t.Errorf("workers=%d: missing cues %v", workers, cp.missing())
+			}
+		}
+	}
+}
+
+// mergeEngine 收到超过 max 条字幕时把最后两条合并成一条，模拟模型漏行；bad 中的字幕始终返回空结果
+type mergeEngine struct {
+	upperEngine
+	max int
+	bad string
+}
+
+func (e *mergeEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	for _, t := range text {
+		if t == e.bad {
+			return nil, nil
+		}
+	}
+	result, _ := e.upperEngine.Translate(ctx, text, sourceLang, targetLang)
+	if len(result) > e.max {
+		n := len(result)
+		result = append(result[:n-2], result[n-2]+" "+result[n-1])
+	}
+	return result, nil
+}
+
+func Test_processText_align(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line" + strings.Repeat("x", i)
+	}
+	tests := []struct {
+		name       string
+		bad        string
+		strict     bool
+		wantSplit  int
+		wantFailed []int
+		wantErr    bool
+	}{
+		{name: "split", wantSplit: 20},
+		{name: "failed", bad: lines[7], wantSplit: 19, wantFailed: []int{7}},
+		{name: "strict", bad: lines[7], strict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strictAlign = tt.strict
+			defer func() { strictAlign = false }()
+			client := &mergeEngine{max: 3, bad: tt.bad}
+			cp := newCheckpoint(filepath.Join(t.TempDir(), "cp.json"), lines)
+			got, report, err := processText(context.Background(), client, lines, 10, 3, 1, cp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("processText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(report.Split()) != tt.wantSplit {
+				t.Errorf("split = %v, want %d cues", report.Split(), tt.wantSplit)
+			}
+			if !reflect.DeepEqual(report.Failed(), tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", report.Failed(), tt.wantFailed)
+			}
+			for i := range lines {
+				want := strings.ToUpper(lines[i])
+				if lines[i] == tt.bad {
+					want = ""
+				}
+				if got[i] != want {
+					t.Errorf("cue %d = %q, want %q", i, got[i], want)
+				}
+			}
+			if len(cp.missing()) != len(tt.wantFailed) {
+				t.Errorf("missing cues %v, want %v", cp.missing(), tt.wantFailed)
+			}
+		})
+	}
+}
+
+func Test_checkAlignment(t *testing.T) {
+	if err := checkAlignment([]string{"a", "b"}, []string{"A", "B"}); err != nil {
+		t.Errorf("checkAlignment() = %v", err)
+	}
+	if err := checkAlignment([]string{"a", "b"}, []string{"A B"}); err == nil {
+		t.Error("checkAlignment() should fail on merged cues")
+	}
+}
+
+// flakyEngine 翻译包含 bad 的块时失败
+type flakyEngine struct {
+	upperEngine
+	bad string
+}
+
+func (e *flakyEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	for _, t := range text {
+		if t == e.bad {
+			return nil, errors.New("rate limited")
+		}
+	}
+	return e.upperEngine.Translate(ctx, text, sourceLang, targetLang)
+}
+
+func Test_processText_fallback(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line" + strings.Repeat("x", i)
+	}
+	client := fallback.NewClient([]fallback.Engine{
+		{Name: "deeplx", Client: &flakyEngine{bad: lines[12]}},
+		{Name: "baidu", Client: &upperEngine{}},
+	}, logger)
+	cp := newCheckpoint(filepath.Join(t.TempDir(), "cp.json"), lines)
+	got, report, err := processText(context.Background(), client, lines, 10, 0, 1, cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range lines {
+		if got[i] != strings.ToUpper(lines[i]) {
+			t.Errorf("cue %d = %q", i, got[i])
+		}
+	}
+	engines := report.Engines()
+	if len(engines["deeplx"]) != 10 || len(engines["baidu"]) != 10 || engines["baidu"][0] != 10 {
+		t.Errorf("Engines() = %v", engines)
+	}
+}
+
+// errorEngine 按 fail 的返回值模拟引擎错误
+type errorEngine struct {
+	upperEngine
+	calls int
+	fail  func(call int, text []string) error
+}
+
+func (e *errorEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	e.calls++
+	if err := e.fail(e.calls, text); err != nil {
+		return nil, err
+	}
+	return e.upperEngine.Translate(ctx, text, sourceLang, targetLang)
+}
+
+func Test_processText_errors(t *testing.T) {
+	rateLimitWait = time.Millisecond
+	defer func() { rateLimitWait = time.Second }()
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = "line" + strings.Repeat("x", i)
+	}
+	tests := []struct {
+		name       string
+		fail       func(call int, text []string) error
+		wantCalls  int
+		wantFailed []int
+		wantErr    error
+	}{
+		{
+			name: "rate limited",
+			fail: func(call int, text []string) error {
+				if call <= 2 {
+					return &api.Error{Kind: api.KindRateLimited, RetryAfter: time.Millisecond}
+				}
+				return nil
+			},
+			wantCalls: 4,
+		},
+		{
+			name: "too long",
+			fail: func(call int, text []string) error {
+				if len(text) > 5 {
+					return api.ErrTooLong
+				}
+				return nil
+			},
+			wantCalls: 6,
+		},
+		{
+			name: "single cue too long",
+			fail: func(call int, text []string) error {
+				for _, t := range text {
+					if t == lines[7] {
+						return api.ErrTooLong
+					}
+				}
+				return nil
+			},
+			wantCalls:  8,
+			wantFailed: []int{7},
+		},
+		{
+			name:      "auth",
+			fail:      func(call int, text []string) error { return api.ErrAuth },
+			wantCalls: 1,
+			wantErr:   api.ErrAuth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &errorEngine{fail: tt.fail}
+			cp := newCheckpoint(filepath.Join(t.TempDir(), "cp.json"), lines)
+			got, report, err := processText(context.Background(), client, lines, 10, 0, 1, cp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("processText() error = %v, want %v", err, tt.wantErr)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", client.calls, tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(report.Failed(), tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", report.Failed(), tt.wantFailed)
+			}
+			for i := range lines {
+				if got[i] == "" && !reflect.DeepEqual(tt.wantFailed, []int{i}) {
+					t.Errorf("cue %d not translated", i)
+				}
+			}
+		})
+	}
+}
+
+// emptyEngine 对 empty 中的字幕返回空译文
+type emptyEngine struct {
+	upperEngine
+	empty string
+}
+
+func (e *emptyEngine) Translate(ctx context.Context, text []string, sourceLang string, targetLang string) ([]string, error) {
+	result, _ := e.upperEngine.Translate(ctx, text, sourceLang, targetLang)
+	for i, t := range text {
+		if t == e.empty {
+			result[i] = ""
+		}
+	}
+	return result, nil
+}
+
+func Test_processText_emptyCues(t *testing.T) {
+	lines := []string{"a", " ", "b", "c"}
+	path := filepath.Join(t.TempDir(), "cp.json")
+	cp := newCheckpoint(path, lines)
+	if !cp.done(1) {
+		t.Error("cue with empty source should be done")
+	}
+	got, _, err := processText(context.Background(), &emptyEngine{empty: "b"}, lines, 10, 0, 1, cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"A", "", "", "C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("processText() = %q, want %q", got, want)
+	}
+	if missing := cp.missing(); len(missing) != 0 {
+		t.Errorf("missing cues %v, want none", missing)
+	}
+	loaded, err := loadCheckpoint(path, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := loaded.missing(); len(missing) != 0 {
+		t.Errorf("loaded checkpoint missing cues %v, want none", missing)
+	}
+}
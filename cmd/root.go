@@ -4,9 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/AnTengye/srtt/api/cache"
+	"github.com/AnTengye/srtt/api/tm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -14,6 +20,8 @@ import (
 
 var cfgFile string
 var debug bool
+var cacheDir string
+var tmDir string
 var logger *zap.SugaredLogger
 
 // rootCmd represents the base command when called without any subcommands
@@ -26,8 +34,11 @@ translation using various translation engines like DeepL and Baidu. It's designe
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Ctrl-C / SIGTERM cancels the command context so in-flight requests are aborted.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := rootCmd.ExecuteContext(ctx)
 	if err != nil {
 		os.Exit(1)
 	}
@@ -44,6 +55,8 @@ func init() {
 	logger = l.Sugar()
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.srtt.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "", false, "Debug mode")
+	rootCmd.PersistentFlags().StringVarP(&cacheDir, "cache-dir", "", cache.DefaultDir(), "Translation cache directory")
+	rootCmd.PersistentFlags().StringVarP(&tmDir, "tm-dir", "", tm.DefaultDir(), "Translation memory directory")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
@@ -63,7 +76,9 @@ func initConfig() {
 		viper.SetConfigName(".srtt")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	viper.SetEnvPrefix("srtt")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv() // read in environment variables that match, e.g. SRTT_ENGINES_BAIDU_APP_ID
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AnTengye/srtt/api/tm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tmSourceLang string
+	tmTargetLang string
+	tmLimit      int
+	tmMinScore   float64
+)
+
+// tmCmd represents the translation memory command
+var tmCmd = &cobra.Command{
+	Use:   "tm",
+	Short: "manage translation memory",
+	Long: `
+manage the translation memory stored in --tm-dir
+`,
+}
+
+var tmImportCmd = &cobra.Command{
+	Use:   "import <file.tmx>",
+	Short: "import translations from a TMX file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		f, err := os.Open(args[0])
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer f.Close()
+		units, err := tm.ReadTMX(f, tmSourceLang, tmTargetLang)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		store := openTM()
+		defer store.Close()
+		n, err := store.Add(units)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		logger.Infof("已导入 %d 条译文到 %s", n, store.Path())
+	},
+}
+
+var tmExportCmd = &cobra.Command{
+	Use:   "export <file.tmx>",
+	Short: "export translations to a TMX file, - for stdout",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store := openTM()
+		defer store.Close()
+		units, err := store.All(tmSourceLang, tmTargetLang)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		w := os.Stdout
+		if args[0] != "-" {
+			if w, err = os.Create(args[0]); err != nil {
+				logger.Fatal(err)
+			}
+		}
+		if err := tm.WriteTMX(w, units); err != nil {
+			logger.Fatal(err)
+		}
+		if args[0] != "-" {
+			if err := w.Close(); err != nil {
+				logger.Fatal(err)
+			}
+			logger.Infof("已导出 %d 条译文到 %s", len(units), args[0])
+		}
+	},
+}
+
+var tmSearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "search similar translations",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store := openTM()
+		defer store.Close()
+		matches, err := store.Search(tmSourceLang, tmTargetLang, strings.Join(args, " "), tmMinScore, tmLimit)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		for _, m := range matches {
+			fmt.Printf("%3.0f%%  %s\n      %s\n", m.Score*100, m.Source, m.Target)
+		}
+		if len(matches) == 0 {
+			logger.Infof("没有相似度不低于 %.2f 的译文", tmMinScore)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tmCmd)
+	tmCmd.AddCommand(tmImportCmd, tmExportCmd, tmSearchCmd)
+
+	tmImportCmd.Flags().StringVarP(&tmSourceLang, "source", "s", "", "Source language, default is the srclang of the TMX header")
+	tmImportCmd.Flags().StringVarP(&tmTargetLang, "target", "t", "", "Target language, default imports every other language")
+	tmExportCmd.Flags().StringVarP(&tmSourceLang, "source", "s", "", "Only export this source language")
+	tmExportCmd.Flags().StringVarP(&tmTargetLang, "target", "t", "", "Only export this target language")
+	tmSearchCmd.Flags().StringVarP(&tmSourceLang, "source", "s", "ja", "Source language")
+	tmSearchCmd.Flags().StringVarP(&tmTargetLang, "target", "t", "zh", "Target language")
+	tmSearchCmd.Flags().Float64VarP(&tmMinScore, "threshold", "", 0.5, "Minimum similarity (0-1)")
+	tmSearchCmd.Flags().IntVarP(&tmLimit, "limit", "", 10, "Maximum number of results")
+}
+
+func openTM() *tm.Store {
+	store, err := tm.Open(tmDir)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	return store
+}
@@ -4,27 +4,19 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/AnTengye/srtt/api"
-	"github.com/AnTengye/srtt/api/baidu"
-	"github.com/AnTengye/srtt/api/chatgpt"
-	"github.com/AnTengye/srtt/api/deeplx"
-	"github.com/AnTengye/srtt/api/google"
+	"github.com/AnTengye/srtt/api/cache"
+	"github.com/AnTengye/srtt/api/fallback"
+	"github.com/AnTengye/srtt/api/glossary"
+	"github.com/AnTengye/srtt/api/llm"
+	"github.com/AnTengye/srtt/api/tm"
+	"github.com/AnTengye/srtt/subtitle"
 	"github.com/spf13/cobra"
-	"golang.org/x/time/rate"
-)
-
-// support engine
-const (
-	deeplxEngine  = "deeplx"
-	baiduEngine   = "baidu"
-	chatgptEngine = "chatgpt"
-	googleEngine  = "google"
 )
 
 var (
@@ -32,6 +24,12 @@ var (
 	targetLang     string
 	inputFilePath  string
 	outputFilePath string
+	subFormat      string
+
+	bilingual      bool
+	bilingualOrder string
+	bilingualSep   string
+	bilingualStyle string
 	processLength  int
 	contextOffset  int
 	coffeeLength   int
@@ -43,9 +41,23 @@ var (
 	retry         int
 	retryWaitTime int
 
-	key      string
-	secret   string
-	gptModel string
+	key          string
+	secret       string
+	gptModel     string
+	promptFile   string
+	glossaryFile string
+	useTM        bool
+	tmRecord     bool
+	tmThreshold  float64
+	tmExamples   int
+	engineConfig map[string]string
+	noCache      bool
+	resume       bool
+	workers      int
+	strictAlign  bool
+
+	timeout        time.Duration
+	requestTimeout time.Duration
 )
 
 // translateCmd represents the translation command
@@ -65,20 +77,39 @@ func init() {
 	translateCmd.Flags().StringVarP(&targetLang, "target", "t", "zh", "Target language")
 	translateCmd.Flags().StringVarP(&inputFilePath, "input", "i", "ja.srt", "Input file path")
 	translateCmd.Flags().StringVarP(&outputFilePath, "output", "o", "", "Output file path")
-	translateCmd.Flags().IntVarP(&processLength, "processLength", "", 10, "Length of each process")
+	translateCmd.Flags().StringVarP(&subFormat, "format", "f", "", "Subtitle format: srt, vtt, ass, ssa. Detected from input extension by default")
+	translateCmd.Flags().BoolVarP(&bilingual, "bilingual", "", false, "Write both the source and the translated text into each cue")
+	translateCmd.Flags().StringVarP(&bilingualOrder, "bilingual-order", "", "source-first", "Bilingual order: source-first, target-first")
+	translateCmd.Flags().StringVarP(&bilingualSep, "bilingual-sep", "", `\n`, "Bilingual separator for srt/vtt")
+	translateCmd.Flags().StringVarP(&bilingualStyle, "bilingual-style", "", "Secondary", "Style name of the second language line for ass/ssa")
+	translateCmd.Flags().IntVarP(&processLength, "processLength", "", 0, "Maximum cues per request, 0 fills each request up to the engine limits")
 	translateCmd.Flags().IntVarP(&contextOffset, "ctxOffset", "", 3, "Context offset")
+	translateCmd.Flags().IntVarP(&workers, "workers", "", 1, "Number of blocks translated concurrently")
+	translateCmd.Flags().BoolVarP(&strictAlign, "strict-align", "", false, "Stop when a cue still cannot be aligned after splitting the block down to single cues")
 
 	translateCmd.Flags().IntVarP(&coffeeLength, "coffeeLength", "", 0, "Drink coffee times. 0 means no coffee")
 	translateCmd.Flags().IntVarP(&coffeeTime, "coffeeTime", "", 0, "Drink coffee need time, you should set coffeeLength. 0 means no coffee")
 
-	translateCmd.Flags().StringVarP(&engine, "engine", "e", "deeplx", "Translation engine: deeplx, baidu")
+	translateCmd.Flags().StringVarP(&engine, "engine", "e", "deeplx", "Translation engine: "+strings.Join(engineNames(), ", ")+". A comma separated list (e.g. deeplx,baidu) falls back to the next engine when a block fails. See 'srtt engines list'")
+	translateCmd.Flags().StringToStringVarP(&engineConfig, "engine-config", "", nil, "Engine settings as name=value, see 'srtt engines list'")
 	translateCmd.Flags().StringVarP(&baseUrl, "apiUrl", "", "", "API base url")
 	translateCmd.Flags().IntVarP(&retry, "retry", "", 3, "Retry times")
 	translateCmd.Flags().IntVarP(&retryWaitTime, "retryWT", "", 500, "Retry wait time(ms)")
+	translateCmd.Flags().DurationVarP(&timeout, "timeout", "", 0, "Timeout of the whole translation job, 0 means no timeout")
+	translateCmd.Flags().DurationVarP(&requestTimeout, "request-timeout", "", 0, "Timeout of each translation request, 0 means no timeout")
 
 	translateCmd.Flags().StringVarP(&key, "apiKey", "", "", "Api key, required for some engine")
 	translateCmd.Flags().StringVarP(&secret, "apiSecret", "", "", "Api secret, required for some engine")
+	translateCmd.Flags().StringVarP(&azureRegion, "azure-region", "", "", "Azure Translator resource region")
 	translateCmd.Flags().StringVarP(&gptModel, "gptModel", "", "gpt-3.5-turbo", "GPT model")
+	translateCmd.Flags().StringVarP(&promptFile, "prompt-file", "", "", "Prompt template file (Go text/template) for LLM engines")
+	translateCmd.Flags().StringVarP(&glossaryFile, "glossary", "", "", "Glossary file (.csv, .tsv or .yaml) with terms that must be translated consistently")
+	translateCmd.Flags().BoolVarP(&useTM, "tm", "", false, "Use the translation memory in --tm-dir")
+	translateCmd.Flags().BoolVarP(&tmRecord, "tm-record", "", false, "Record the translations of this run in the translation memory as approved, only use it for reviewed output")
+	translateCmd.Flags().Float64VarP(&tmThreshold, "tm-threshold", "", 0.75, "Minimum similarity (0-1) of translation memory entries offered to LLM engines as examples")
+	translateCmd.Flags().IntVarP(&tmExamples, "tm-examples", "", 3, "Maximum number of translation memory examples per request for LLM engines, 0 disables them")
+	translateCmd.Flags().BoolVarP(&noCache, "no-cache", "", false, "Disable the translation cache")
+	translateCmd.Flags().BoolVarP(&resume, "resume", "", false, "Resume from the checkpoint file next to the output file")
 }
 
 func translateRun(cmd *cobra.Command, args []string) {
@@ -86,137 +117,264 @@ func translateRun(cmd *cobra.Command, args []string) {
 	defer func() {
 		logger.Infof("耗时： %d s\n", int(time.Since(startTime).Seconds()))
 	}()
-	file, err := os.Open(inputFilePath)
+	format, err := resolveFormat(subFormat, inputFilePath)
 	if err != nil {
-		logger.Fatal(err.Error())
-	}
-	defer file.Close()
-	var lines []string
-	var srtLines []string
-	scanner := bufio.NewScanner(file)
-	srtIndex := 0
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-		if srtIndex%4 == 2 {
-			srtLines = append(srtLines, scanner.Text())
+		logger.Fatal(err)
+	}
+	doc, err := readSubtitle(inputFilePath, format)
+	if err != nil {
+		logger.Fatalf("字幕解析失败: %s", err)
+	}
+	if bilingual && bilingualOrder != "source-first" && bilingualOrder != "target-first" {
+		logger.Fatalf("不支持的双语顺序: %s", bilingualOrder)
+	}
+	srtLines := doc.Texts()
+	logger.Infof("字幕条数: %d", len(srtLines))
+	names := strings.Split(engine, ",")
+	var engines []api.Engine
+	for _, name := range names {
+		e, ok := api.Lookup(strings.TrimSpace(name))
+		if !ok {
+			logger.Fatalf("暂时不支持的翻译引擎: %s，可用引擎: %s", name, strings.Join(engineNames(), ", "))
+		}
+		for _, prev := range engines {
+			if prev.Name == e.Name {
+				logger.Fatalf("引擎 %s 重复出现在 --engine 中", e.Name)
+			}
 		}
-		srtIndex++
+		engines = append(engines, e)
+	}
+	if err := checkChainConfig(engines); err != nil {
+		logger.Fatal(err)
+	}
+	var terms *glossary.Glossary
+	if glossaryFile != "" {
+		if terms, err = glossary.Load(glossaryFile); err != nil {
+			logger.Fatalf("术语表加载失败: %s", err)
+		}
+		logger.Infof("已加载术语表: %d 条", len(terms.Entries))
+	}
+	var store *cache.Store
+	if !noCache {
+		if store, err = cache.Open(cacheDir); err != nil {
+			logger.Warnf("翻译缓存不可用: %s", err)
+		} else {
+			defer store.Close()
+		}
+	}
+	ctx := cmd.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	logger.Infof("文本行数: %d", len(srtLines))
 	var apiClient api.TranslateApi
-	switch engine {
-	case deeplxEngine:
-		apiClient = deeplx.NewClient(logger.With("engine", engine),
-			deeplx.WithBaseUrl(baseUrl),
-			deeplx.WithDebug(debug),
-			deeplx.WithRetry(retry),
-			deeplx.WithRetryWaitTime(time.Duration(retryWaitTime)*time.Millisecond),
-		)
-	case baiduEngine:
-		if key == "" || secret == "" {
-			logger.Fatal("baidu api key or secret is empty")
+	var chain []fallback.Engine
+	examples := 0
+	for i, e := range engines {
+		client, usesExamples, err := newEngine(ctx, cmd, e, i == 0, len(engines) > 1, terms, store)
+		if err != nil {
+			if i == 0 {
+				logger.Fatal(err)
+			}
+			logger.Warnf("备用引擎 %s 不可用，已跳过: %s", e.Name, err)
+			continue
+		}
+		// 相似译文只对会把参考译文写入提示词的引擎有用
+		if usesExamples {
+			examples = tmExamples
 		}
-		apiClient = baidu.NewClient(key, secret, logger.With("engine", engine),
-			baidu.WithBaseUrl(baseUrl),
-			baidu.WithDebug(debug),
-			baidu.WithRetry(retry),
-			baidu.WithRetryWaitTime(time.Duration(retryWaitTime)*time.Millisecond),
-		)
-	case chatgptEngine:
-		apiClient = chatgpt.NewClient(key, logger.With("engine", engine),
-			chatgpt.WithBaseUrl(baseUrl),
-			chatgpt.WithModel(gptModel),
-			chatgpt.WithCtxOffset(contextOffset),
-		)
-	case googleEngine:
-		apiClient = google.NewClient(key, secret, logger.With("engine", engine), true)
-	default:
-		logger.Fatal("暂时不支持的翻译引擎")
+		chain = append(chain, fallback.Engine{Name: e.Name, Client: client})
+	}
+	if len(chain) == 1 {
+		apiClient = chain[0].Client
+	} else {
+		apiClient = fallback.NewClient(chain, logger)
 	}
 	defer apiClient.Close()
+	var memory *tm.Store
+	if useTM || tmRecord {
+		if memory, err = tm.Open(tmDir); err != nil {
+			logger.Fatalf("翻译记忆不可用: %s", err)
+		}
+		defer memory.Close()
+	}
+	if useTM {
+		apiClient = tm.NewClient(apiClient, memory, tmThreshold, examples, logger.With("engine", engine))
+	}
 	if coffeeLength != 0 && coffeeTime != 0 {
 		logger.Infof("已进入咖啡厅（速率限制模式）")
 	}
+	if outputFilePath == "" {
+		outputFilePath = formatFileNameWithoutExtension(inputFilePath, targetLang, doc.Format)
+	}
+	cp := newCheckpoint(checkpointPath(outputFilePath), srtLines)
+	if resume {
+		cp, err = loadCheckpoint(checkpointPath(outputFilePath), srtLines)
+		if err != nil {
+			logger.Fatalf("无法续翻: %s", err)
+		}
+		logger.Infof("已恢复翻译进度: %d/%d", cp.Total-len(cp.missing()), cp.Total)
+	}
 	logger.Infof("开始翻译,当前引擎: %s", engine)
-	translatedText := processText(apiClient, srtLines, processLength, contextOffset)
+	translatedText, report, err := processText(ctx, apiClient, srtLines, processLength, contextOffset, workers, cp)
+	if err != nil {
+		logger.Fatalf("翻译已停止: %s，修正后可使用 --resume 继续", err)
+	}
+	if len(chain) > 1 {
+		reportEngines(chain, report)
+	}
+	if split := report.Split(); len(split) > 0 {
+		logger.Warnf("%d 条字幕的译文条数不一致，经拆分后对齐: %s", len(split), formatCueRanges(split))
+	}
+	if failed := report.Failed(); len(failed) > 0 {
+		logger.Errorf("%d 条字幕无法对齐，未采用译文: %s", len(failed), formatCueRanges(failed))
+	}
+	var violations []glossary.Violation
+	if terms != nil {
+		violations = reportGlossary(terms, srtLines, translatedText)
+	}
+	if tmRecord {
+		recordTM(memory, srtLines, translatedText, violations)
+	}
+	if ctx.Err() != nil {
+		logger.Warnf("翻译已中断: %s", context.Cause(ctx))
+	}
+	if missing := cp.missing(); len(missing) > 0 {
+		logger.Warnf("%d 条字幕翻译失败: %s，可使用 --resume 重试", len(missing), formatCueRanges(missing))
+	} else if err := cp.remove(); err != nil {
+		logger.Warnf("删除进度文件失败: %s", err)
+	}
 	// 将翻译后的文本写入文件
-	if outputFilePath == "" {
-		outputFilePath = formatFileNameWithoutExtension(inputFilePath, targetLang)
+	if bilingual {
+		err = doc.SetBilingual(translatedText, subtitle.BilingualOptions{
+			TranslationFirst: bilingualOrder == "target-first",
+			Separator:        strings.ReplaceAll(bilingualSep, `\n`, "\n"),
+			Style:            bilingualStyle,
+		})
+	} else {
+		err = doc.SetTexts(translatedText)
 	}
-	translatedFile, err := os.Create(outputFilePath)
 	if err != nil {
 		logger.Fatal(err)
 	}
-	defer translatedFile.Close()
-	for i := 0; i < len(lines); i += 1 {
-		if i%4 == 2 {
-			_, err := translatedFile.WriteString(translatedText[i/4] + "\n")
-			if err != nil {
-				logger.Fatal(err)
-			}
-		} else {
-			_, err := translatedFile.WriteString(lines[i] + "\n")
-			if err != nil {
-				logger.Fatal(err)
-			}
-		}
+	if err := writeSubtitle(outputFilePath, doc); err != nil {
+		logger.Fatal(err)
 	}
 
 	logger.Infof("翻译完成, 结果已保存到 %s", outputFilePath)
 }
 
-func processText(client api.TranslateApi, lines []string, blockSize, overlap int) []string {
-	translatedText := make([]string, len(lines))
-	var throttler *rate.Limiter
-	if coffeeLength != 0 && coffeeTime != 0 {
-		throttler = rate.NewLimiter(
-			rate.Every(time.Duration(coffeeTime)*time.Second),
-			coffeeLength,
-		)
-	}
-	for i := 0; i < len(lines); i += blockSize - overlap {
-		if throttler != nil {
-			reserve := throttler.Reserve()
-			if reserve.Delay() != 0 {
-				logger.Infof("已翻译%d行，喝杯咖啡需花费%d秒", i, int(reserve.Delay().Seconds()))
-				time.Sleep(reserve.Delay())
-			}
+// newEngine 创建引擎并按需加上术语表和缓存。primary 为链中的第一个引擎，只有它读取旧参数；
+// 语言校验与翻译请求一样受 --timeout 和 --request-timeout 限制
+func newEngine(ctx context.Context, cmd *cobra.Command, e api.Engine, primary bool, chain bool, terms *glossary.Glossary, store *cache.Store) (api.TranslateApi, bool, error) {
+	engineCfg, err := e.Config(mergeEngineValues(cmd, e, primary, chain))
+	if err != nil {
+		return nil, false, err
+	}
+	log := logger.With("engine", e.Name)
+	client, err := e.New(engineCfg, log)
+	if err != nil {
+		return nil, false, err
+	}
+	if v, ok := client.(api.LanguageValidator); ok {
+		if requestTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+			defer cancel()
+		}
+		if err := v.ValidateLanguages(ctx, sourceLang, targetLang); err != nil {
+			client.Close()
+			return nil, false, err
 		}
-		// 确保不会超出切片范围
-		end := i + blockSize
-		if end > len(lines) {
-			end = len(lines)
+	}
+	u, ok := client.(llm.ExampleUser)
+	usesExamples := ok && u.UsesExamples()
+	variant := cacheVariant(engineCfg)
+	protectTerms := false
+	if terms != nil {
+		if u, ok := client.(llm.GlossaryUser); ok {
+			u.SetGlossary(promptTerms(terms))
+			variant += "\x00glossary:" + terms.Digest()
+		} else {
+			protectTerms = true
 		}
+	}
+	if store != nil {
+		client = cache.NewClient(client, store, e.Name, variant, log)
+	}
+	// 占位符在缓存之外替换和还原，修改术语译法后缓存仍然有效
+	if protectTerms {
+		client = glossary.NewClient(client, terms)
+	}
+	return client, usesExamples, nil
+}
 
-		// 提取当前窗口的行
-		currentBlock := lines[i:end]
-		logger.Infof("正在翻译第%d-%d行", i+1, end)
-		logger.Debugf("原文：\n %s", strings.Join(currentBlock, "\n----\n"))
-		// 调用处理函数
-		result, err := client.Translate(currentBlock, sourceLang, targetLang)
-		if err != nil {
-			logger.Errorf("第%d-%d行翻译失败: %s", i+1, end, err)
-			continue
+// cacheVariant 区分同一引擎下影响译文的设置：模型和提示词
+func cacheVariant(cfg api.Config) string {
+	variant := strings.Join([]string{cfg.String("model"), cfg.String("prompt_file"), cfg.String("synopsis"), cfg.String("style")}, "\x00")
+	return strings.TrimRight(variant, "\x00")
+}
+
+// promptTerms 将术语表转为提示词中的术语列表
+func promptTerms(g *glossary.Glossary) []llm.Term {
+	terms := make([]llm.Term, len(g.Entries))
+	for i, e := range g.Entries {
+		terms[i] = llm.Term{Source: e.Source, Target: e.Target}
+	}
+	return terms
+}
+
+// reportGlossary 列出没有按术语表翻译的字幕
+func reportGlossary(g *glossary.Glossary, source, translated []string) []glossary.Violation {
+	violations := g.Check(source, translated)
+	for _, v := range violations {
+		logger.Warnf("第%d行术语未按术语表翻译: %s => %s，译文: %s", v.Cue+1, v.Source, v.Target, translated[v.Cue])
+	}
+	if len(violations) > 0 {
+		logger.Warnf("共 %d 处术语未按术语表翻译", len(violations))
+	}
+	return violations
+}
+
+// reportEngines 列出引擎链中每个引擎翻译的字幕
+func reportEngines(chain []fallback.Engine, report *alignReport) {
+	cues := report.Engines()
+	for _, e := range chain {
+		if idx := cues[e.Name]; len(idx) > 0 {
+			logger.Infof("引擎 %s 翻译了 %d 条字幕: %s", e.Name, len(idx), formatCueRanges(idx))
 		}
-		logger.Debugf("译文：\n%s", result)
-		if i > 0 {
-			// 如果不是第一轮，则只选择blockSize-overlap个翻译结果
-			for j := overlap; j < len(currentBlock) && j < len(result); j++ {
-				translatedText[i+j] = strings.TrimSpace(result[j])
-			}
-		} else {
-			for j := 0; j < len(currentBlock) && j < len(result); j++ {
-				translatedText[i+j] = strings.TrimSpace(result[j])
-			}
+	}
+	if idx := cues[""]; len(idx) > 0 {
+		logger.Infof("翻译记忆提供了 %d 条字幕: %s", len(idx), formatCueRanges(idx))
+	}
+}
+
+// recordTM 把本次的译文写入翻译记忆，未翻译和违反术语表的字幕不记录
+func recordTM(memory *tm.Store, source, translated []string, violations []glossary.Violation) {
+	skip := map[int]bool{}
+	for _, v := range violations {
+		skip[v.Cue] = true
+	}
+	var units []tm.Unit
+	for i, s := range source {
+		if skip[i] || i >= len(translated) || strings.TrimSpace(translated[i]) == "" {
+			continue
 		}
+		units = append(units, tm.Unit{SourceLang: sourceLang, TargetLang: targetLang, Source: s, Target: translated[i]})
+	}
+	n, err := memory.Add(units)
+	if err != nil {
+		logger.Warnf("写入翻译记忆失败: %s", err)
+		return
 	}
-	return translatedText
+	logger.Infof("已写入翻译记忆: %d 条", n)
 }
 
-func formatFileNameWithoutExtension(fileName, targetLang string) string {
+func formatFileNameWithoutExtension(fileName, targetLang string, format subtitle.Format) string {
 	lastDotIndex := strings.LastIndex(fileName, ".")
 	if lastDotIndex > -1 {
 		fileName = fileName[:lastDotIndex]
 	}
-	return fmt.Sprintf("%s_%s.srt", fileName, targetLang)
+	return fmt.Sprintf("%s_%s.%s", fileName, targetLang, format)
 }
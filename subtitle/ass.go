@@ -0,0 +1,211 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	assDialogue = "Dialogue:"
+	assFormat   = "Format:"
+)
+
+// assOverrideRe 匹配 ASS 的覆盖标签块，如 {\an8}、{\i1}
+var assOverrideRe = regexp.MustCompile(`\{[^{}]*\}`)
+
+// assInlineRe 匹配句中需要保护的覆盖标签块和软换行 \n
+var assInlineRe = regexp.MustCompile(`\{[^{}]*\}|\\n`)
+
+// assPlaceholderRe 匹配待翻译文本中代替句中标签的占位符 {t1}、{t2}…
+var assPlaceholderRe = regexp.MustCompile(`\{t(\d+)\}`)
+
+// assDrawingRe 匹配开启绘图模式的覆盖标签，如 {\p1}
+var assDrawingRe = regexp.MustCompile(`\{[^{}]*\\p[1-9][^{}]*\}`)
+
+// assHardSpace \h 在待翻译文本中以不换行空格表示
+const assHardSpace = "\u00a0"
+
+var defaultASSEvents = []string{"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+
+// assEvents [Events] 段 Format 行定义的字段顺序
+type assEvents []string
+
+func (e assEvents) index(name string) int {
+	for i, f := range e {
+		if strings.EqualFold(f, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseASSTimestamp 解析 H:MM:SS.cc
+func parseASSTimestamp(s string) (time.Duration, error) {
+	return parseTimestamp(strings.TrimSpace(s))
+}
+
+// formatASSTimestamp 以 H:MM:SS.cc 输出时间
+func formatASSTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// assPlainText 将 ASS 文本转换为待翻译文本：
+// 首尾的覆盖标签保存在 markup 中，句中的覆盖标签和软换行 \n 替换为占位符 {tN}，
+// \N 转为换行，\h 转为不换行空格。绘图（{\p1}）不是文字，整行保存在 markup 中，不参与翻译。
+func assPlainText(text string) (markup, string) {
+	if assDrawingRe.MatchString(text) {
+		return markup{prefix: text, drawing: true}, ""
+	}
+	m, plain := splitMarkup(text, assOverrideRe)
+	plain = assInlineRe.ReplaceAllStringFunc(plain, func(tag string) string {
+		m.tags = append(m.tags, tag)
+		return "{t" + strconv.Itoa(len(m.tags)) + "}"
+	})
+	plain = strings.NewReplacer(`\N`, "\n", `\h`, assHardSpace).Replace(plain)
+	return m, plain
+}
+
+// assText 还原 assPlainText 转换的内容；译文中丢失的标签追加到末尾，避免样式影响后续字幕
+func assText(m markup, plain string) string {
+	used := make([]bool, len(m.tags))
+	text := assPlaceholderRe.ReplaceAllStringFunc(plain, func(p string) string {
+		n, _ := strconv.Atoi(assPlaceholderRe.FindStringSubmatch(p)[1])
+		if n < 1 || n > len(m.tags) || used[n-1] {
+			return ""
+		}
+		used[n-1] = true
+		return m.tags[n-1]
+	})
+	text = strings.NewReplacer("\n", `\N`, assHardSpace, `\h`).Replace(text)
+	for i, tag := range m.tags {
+		if !used[i] && tag != `\n` {
+			text += tag
+		}
+	}
+	return m.apply(text)
+}
+
+// assStripTags 去掉 assPlainText 生成的占位符，转换为其他格式时使用
+func assStripTags(plain string) string {
+	return strings.ReplaceAll(assPlaceholderRe.ReplaceAllString(plain, ""), assHardSpace, " ")
+}
+
+func parseASS(doc *Document, content string) error {
+	lines := splitLines(content)
+	events := assEvents(defaultASSEvents)
+	section := ""
+	var prev *Cue
+	var header strings.Builder
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+		}
+		if section == "[events]" && strings.HasPrefix(line, assFormat) {
+			events = nil
+			for _, f := range strings.Split(strings.TrimPrefix(line, assFormat), ",") {
+				events = append(events, strings.TrimSpace(f))
+			}
+		}
+		if section != "[events]" || !strings.HasPrefix(line, assDialogue) {
+			// [Script Info]、[V4+ Styles]、Comment 等内容原样保留
+			if prev == nil {
+				header.WriteString(raw)
+			} else {
+				prev.post += raw
+			}
+			continue
+		}
+		c, err := parseASSDialogue(strings.TrimRight(raw, "\r\n"), events)
+		if err != nil {
+			return err
+		}
+		c.Index = len(doc.Cues) + 1
+		c.snapshot(raw)
+		doc.Cues = append(doc.Cues, c)
+		prev = c
+	}
+	doc.header = header.String()
+	doc.events = events
+	if !strings.Contains(strings.ToLower(doc.header), "[script info]") {
+		return fmt.Errorf("missing [Script Info] section")
+	}
+	return nil
+}
+
+func parseASSDialogue(line string, events assEvents) (*Cue, error) {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, assDialogue)), ",", len(events))
+	if len(fields) != len(events) {
+		return nil, fmt.Errorf("invalid dialogue line: %q", line)
+	}
+	c := &Cue{fields: fields}
+	var err error
+	if i := events.index("Start"); i >= 0 {
+		if c.Start, err = parseASSTimestamp(fields[i]); err != nil {
+			return nil, err
+		}
+	}
+	if i := events.index("End"); i >= 0 {
+		if c.End, err = parseASSTimestamp(fields[i]); err != nil {
+			return nil, err
+		}
+	}
+	if i := events.index("Style"); i >= 0 {
+		c.Style = fields[i]
+	}
+	if i := events.index("Text"); i >= 0 {
+		c.markup, c.Text = assPlainText(fields[i])
+	}
+	return c, nil
+}
+
+func formatASSDialogue(c *Cue, events assEvents) string {
+	if events == nil {
+		events = defaultASSEvents
+	}
+	fields := make([]string, len(events))
+	copy(fields, c.fields)
+	for i, name := range events {
+		switch strings.ToLower(name) {
+		case "start":
+			fields[i] = formatASSTimestamp(c.Start)
+		case "end":
+			fields[i] = formatASSTimestamp(c.End)
+		case "style":
+			fields[i] = c.Style
+		case "text":
+			fields[i] = assText(c.markup, c.Text)
+		case "layer", "marginl", "marginr", "marginv":
+			if fields[i] == "" {
+				fields[i] = "0"
+			}
+		}
+	}
+	return assDialogue + " " + strings.Join(fields, ",")
+}
+
+func writeASS(buf *bytes.Buffer, doc *Document) {
+	buf.WriteString(doc.header)
+	for _, c := range doc.Cues {
+		if c.unchanged() {
+			buf.WriteString(c.raw)
+		} else {
+			buf.WriteString(formatASSDialogue(c, doc.events) + doc.newline)
+		}
+		if c.secondary != nil {
+			if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+				buf.WriteString(doc.newline)
+			}
+			buf.WriteString(formatASSDialogue(c.secondary, doc.events) + doc.newline)
+		}
+		buf.WriteString(c.post)
+	}
+}
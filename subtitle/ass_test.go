@@ -0,0 +1,87 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const assSample = "[Script Info]\r\n" +
+	"Title: sample\r\n" +
+	"ScriptType: v4.00+\r\n" +
+	"\r\n" +
+	"[V4+ Styles]\r\n" +
+	"Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\r\n" +
+	"Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1\r\n" +
+	"\r\n" +
+	"[Events]\r\n" +
+	"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\r\n" +
+	"Dialogue: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,{\\an8}何だよ、\\Nそれ{\\i0}\r\n" +
+	"Comment: 0,0:00:02.00,0:00:03.00,Default,,0,0,0,,注释, 不翻译\r\n" +
+	"Dialogue: 0,0:00:03.00,0:00:04.00,Default,Roger,0,0,0,,一つ{\\i1}二つ{\\i0}, 三つ\r\n"
+
+func TestParseASS(t *testing.T) {
+	doc, err := Parse(strings.NewReader(assSample), ASS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"何だよ、\nそれ", "一つ{t1}二つ{t2}, 三つ"}
+	if got := doc.Texts(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Texts() = %q, want %q", got, want)
+	}
+	c := doc.Cues[0]
+	if c.Start != time.Second || c.End != 2500*time.Millisecond || c.Style != "Default" {
+		t.Errorf("cue 0 = %+v", c)
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != assSample {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), assSample)
+	}
+}
+
+func TestWriteASS(t *testing.T) {
+	doc, err := Parse(strings.NewReader(assSample), ASS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetTexts([]string{"什么啊，\n那是", "一个{t1}两个{t2}，三个"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"{\\an8}何だよ、\\Nそれ{\\i0}", "{\\an8}什么啊，\\N那是{\\i0}",
+		"一つ{\\i1}二つ{\\i0}, 三つ", "一个{\\i1}两个{\\i0}，三个",
+	).Replace(assSample)
+	if buf.String() != want {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestASSInlineTags(t *testing.T) {
+	tests := []struct {
+		name, text, plain, translated, want string
+	}{
+		{name: "tags", text: `a{\c&H0000FF&}b{\r}c`, plain: "a{t1}b{t2}c", translated: "A{t1}B{t2}C", want: `A{\c&H0000FF&}B{\r}C`},
+		{name: "lost tag", text: `a{\i1}b{\i0}c`, plain: "a{t1}b{t2}c", translated: "AB{t1}C", want: `AB{\i1}C{\i0}`},
+		{name: "spaces and breaks", text: `a\hb\nc\Nd`, plain: "a\u00a0b{t1}c\nd", translated: "A\u00a0B{t1}C\nD", want: `A\hB\nC\ND`},
+		{name: "drawing", text: `{\p1}m 0 0 l 100 0 100 100{\p0}`, plain: "", translated: "", want: `{\p1}m 0 0 l 100 0 100 100{\p0}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, plain := assPlainText(tt.text)
+			if plain != tt.plain {
+				t.Errorf("assPlainText() = %q, want %q", plain, tt.plain)
+			}
+			if got := assText(m, tt.translated); got != tt.want {
+				t.Errorf("assText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultSecondaryStyle = "Secondary"
+
+// BilingualOptions 双语字幕的输出方式
+type BilingualOptions struct {
+	TranslationFirst bool   // 译文在前
+	Separator        string // SRT/VTT 中两种语言之间的分隔符，默认换行
+	Style            string // ASS/SSA 中第二语言 Dialogue 行的样式名，默认 Secondary
+}
+
+// SetBilingual 在每条字幕中同时保留原文和译文。
+// SRT/VTT 将两种语言写入同一条字幕；ASS/SSA 将第二语言输出为单独的 Dialogue 行，
+// 并在样式表中以第一条字幕的样式为模板添加第二语言的样式。
+// 译文为空的字幕和 ASS 绘图行只保留原文。
+func (d *Document) SetBilingual(texts []string, opts BilingualOptions) error {
+	if len(texts) != len(d.Cues) {
+		return fmt.Errorf("text count %d does not match cue count %d", len(texts), len(d.Cues))
+	}
+	if opts.Separator == "" {
+		opts.Separator = "\n"
+	}
+	if opts.Style == "" {
+		opts.Style = defaultSecondaryStyle
+	}
+	for i, c := range d.Cues {
+		if strings.TrimSpace(texts[i]) == "" || c.markup.drawing {
+			continue
+		}
+		first, second := c.Text, texts[i]
+		if opts.TranslationFirst {
+			first, second = second, first
+		}
+		if !isASS(d.Format) {
+			c.Text = first + opts.Separator + second
+			continue
+		}
+		c.Text = first
+		c.secondary = &Cue{
+			Start:  c.Start,
+			End:    c.End,
+			Text:   second,
+			Style:  opts.Style,
+			markup: c.markup,
+			fields: c.fields,
+		}
+	}
+	if isASS(d.Format) && len(d.Cues) > 0 {
+		d.header = addASSStyle(d.header, d.Cues[0].Style, opts.Style, d.newline)
+	}
+	return nil
+}
+
+// addASSStyle 复制样式表中名为 base 的样式并命名为 name，样式已存在时不做修改
+func addASSStyle(header, base, name, nl string) string {
+	lines := splitLines(header)
+	last := -1
+	var template string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "Style:") {
+			continue
+		}
+		last = i
+		styleName, rest, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(trimmed, "Style:")), ",")
+		if styleName == name {
+			return header
+		}
+		if styleName == base || template == "" {
+			template = rest
+		}
+	}
+	if last < 0 {
+		return header
+	}
+	style := "Style: " + name + "," + template + nl
+	lines = append(lines[:last+1], append([]string{style}, lines[last+1:]...)...)
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,64 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetBilingual(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		content string
+		texts   []string
+		opts    BilingualOptions
+		want    string
+	}{
+		{
+			name:    "srt source first",
+			format:  SRT,
+			content: "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n\n2\n00:00:03,000 --> 00:00:04,000\nさようなら\n",
+			texts:   []string{"你好", ""},
+			want:    "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n你好\n\n2\n00:00:03,000 --> 00:00:04,000\nさようなら\n",
+		},
+		{
+			name:    "vtt translation first",
+			format:  VTT,
+			content: "WEBVTT\n\n00:01.000 --> 00:02.000\n<v A>こんにちは\n",
+			texts:   []string{"你好"},
+			opts:    BilingualOptions{TranslationFirst: true, Separator: " / "},
+			want:    "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<v A>你好 / こんにちは\n\n",
+		},
+		{
+			name:   "ass secondary style",
+			format: ASS,
+			content: "[Script Info]\n\n[V4+ Styles]\nFormat: Name, Fontname, Fontsize\nStyle: Default,Arial,20\n\n[Events]\n" +
+				"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+				"Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\\an8}こんにちは\n",
+			texts: []string{"你好"},
+			want: "[Script Info]\n\n[V4+ Styles]\nFormat: Name, Fontname, Fontsize\nStyle: Default,Arial,20\nStyle: Secondary,Arial,20\n\n[Events]\n" +
+				"Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n" +
+				"Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,{\\an8}こんにちは\n" +
+				"Dialogue: 0,0:00:01.00,0:00:02.00,Secondary,,0,0,0,,{\\an8}你好\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(tt.content), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.SetBilingual(tt.texts, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if _, err := doc.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteTo() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package subtitle
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultASSHeader 由其他格式转换为 ASS 时使用的头部
+const defaultASSHeader = `[Script Info]
+ScriptType: v4.00+
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+YCbCr Matrix: None
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+const defaultASSStyle = "Default"
+
+// htmlTagRe 匹配 SRT 中常见的 <i>、<font color="..."> 等标签
+var htmlTagRe = regexp.MustCompile(`</?[a-zA-Z][^<>\n]*>`)
+
+func isASS(f Format) bool {
+	return f == ASS || f == SSA
+}
+
+// Convert 将字幕转换为另一种格式，返回新文档和转换中丢失信息的说明。
+//
+// 有损转换：
+//   - ASS/SSA 转其他格式：样式、脚本信息、Comment 行和覆盖标签被丢弃
+//   - WebVTT 转其他格式：头部、NOTE/STYLE/REGION 块、cue 标识、cue settings 和首尾行内标签被丢弃
+//   - SRT 转其他格式：X1/X2/Y1/Y2 坐标被丢弃
+//   - 转为 ASS/SSA 时文本中的 <i> 等标签被删除
+func Convert(doc *Document, to Format) (*Document, []string) {
+	out := &Document{Format: to, newline: doc.newline}
+	var warnings []string
+	warn := func(cond bool, msg string) {
+		if cond {
+			warnings = append(warnings, msg)
+		}
+	}
+	if isASS(doc.Format) && isASS(to) {
+		// ASS 与 SSA 之间仅保留原有头部，样式段格式不做转换
+		out.header, out.events = doc.header, doc.events
+		warn(doc.Format != to, "[Script Info] and style sections are copied unchanged between ASS and SSA")
+	} else if isASS(to) {
+		out.header = strings.ReplaceAll(defaultASSHeader, "\n", doc.newline)
+	}
+
+	var hasPost, hasMarkup, hasPosition, hasID, hasSettings, hasStyle, hasTags bool
+	for _, c := range doc.Cues {
+		n := &Cue{
+			Index: c.Index,
+			Start: c.Start,
+			End:   c.End,
+			Text:  c.Text,
+		}
+		hasPost = hasPost || strings.TrimSpace(c.post) != ""
+		hasMarkup = hasMarkup || !c.markup.empty()
+		hasPosition = hasPosition || c.Position != nil
+		hasID = hasID || c.ID != ""
+		hasSettings = hasSettings || c.Settings != ""
+		hasStyle = hasStyle || (c.Style != "" && c.Style != defaultASSStyle)
+		if doc.Format == to || (isASS(doc.Format) && isASS(to)) {
+			n.ID, n.Settings, n.Position, n.Style = c.ID, c.Settings, c.Position, c.Style
+			n.markup, n.fields, n.post = c.markup, c.fields, c.post
+		}
+		if isASS(doc.Format) && !isASS(to) {
+			n.Text = assStripTags(n.Text)
+		}
+		switch to {
+		case SRT:
+			n.Position = c.Position
+		case VTT:
+			n.ID, n.Settings = c.ID, c.Settings
+		case ASS, SSA:
+			if !isASS(doc.Format) {
+				n.Style = defaultASSStyle
+				if htmlTagRe.MatchString(n.Text) {
+					hasTags = true
+					n.Text = htmlTagRe.ReplaceAllString(n.Text, "")
+				}
+			}
+		}
+		out.Cues = append(out.Cues, n)
+	}
+
+	if doc.Format == to {
+		out.header, out.bom = doc.header, doc.bom
+		return out, warnings
+	}
+	switch doc.Format {
+	case ASS, SSA:
+		if !isASS(to) {
+			warn(true, "ASS script info and styles are dropped")
+			warn(hasStyle, "cue styles other than Default are dropped")
+			warn(hasMarkup, "ASS override tags such as {\\an8} are dropped")
+		}
+		warn(hasPost && !isASS(to), "ASS Comment lines and extra sections are dropped")
+	case VTT:
+		warn(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(doc.header), vttSignature)) != "" || hasPost,
+			"WebVTT header, NOTE, STYLE and REGION blocks are dropped")
+		warn(hasID && to != VTT, "WebVTT cue identifiers are dropped")
+		warn(hasSettings && to != VTT, "WebVTT cue settings are dropped")
+		warn(hasMarkup, "WebVTT voice and class tags around cue text are dropped")
+	case SRT:
+		warn(hasPosition, "SRT X1/X2/Y1/Y2 coordinates are dropped")
+	}
+	warn(hasTags, "inline tags such as <i> are removed for ASS output")
+	return out, warnings
+}
@@ -0,0 +1,61 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         Format
+		content      string
+		to           Format
+		want         string
+		wantWarnings int
+	}{
+		{
+			name:         "ass to srt",
+			from:         ASS,
+			content:      strings.ReplaceAll(assSample, "\r\n", "\n"),
+			to:           SRT,
+			want:         "1\n00:00:01,000 --> 00:00:02,500\n何だよ、\nそれ\n\n2\n00:00:03,000 --> 00:00:04,000\n一つ二つ, 三つ\n\n",
+			wantWarnings: 3,
+		},
+		{
+			name:         "srt to vtt",
+			from:         SRT,
+			content:      "1\n00:00:01,000 --> 00:00:02,000 X1:1 X2:2 Y1:3 Y2:4\n<i>A</i>\n",
+			to:           VTT,
+			want:         "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\n<i>A</i>\n\n",
+			wantWarnings: 1,
+		},
+		{
+			name:    "vtt to ass",
+			from:    VTT,
+			content: "WEBVTT\n\n00:01.000 --> 00:02.000\nA\nB\n",
+			to:      ASS,
+			want:    defaultASSHeader + "Dialogue: 0,0:00:01.00,0:00:02.00,Default,,0,0,0,,A\\NB\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(tt.content), tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, warnings := Convert(doc, tt.to)
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("Convert() warnings = %q, want %d", warnings, tt.wantWarnings)
+			}
+			var buf bytes.Buffer
+			if _, err := out.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Convert() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package subtitle
+
+import "regexp"
+
+// markup 字幕文本首尾的格式标签，翻译时剥离，写回时还原
+type markup struct {
+	prefix string
+	suffix string
+	tags   []string // ASS 句中被占位符 {tN} 代替的标签
+	// drawing ASS 绘图行，整行保存在 prefix 中，不写入译文
+	drawing bool
+}
+
+// splitMarkup 剥离文本首尾连续的标签，返回标签和纯文本
+func splitMarkup(text string, tag *regexp.Regexp) (markup, string) {
+	var m markup
+	for {
+		loc := tag.FindStringIndex(text)
+		if loc == nil || loc[0] != 0 {
+			break
+		}
+		m.prefix += text[:loc[1]]
+		text = text[loc[1]:]
+	}
+	for {
+		locs := tag.FindAllStringIndex(text, -1)
+		if len(locs) == 0 {
+			break
+		}
+		last := locs[len(locs)-1]
+		if last[1] != len(text) {
+			break
+		}
+		m.suffix = text[last[0]:] + m.suffix
+		text = text[:last[0]]
+	}
+	return m, text
+}
+
+func (m markup) empty() bool {
+	return m.prefix == "" && m.suffix == "" && len(m.tags) == 0
+}
+
+func (m markup) apply(text string) string {
+	return m.prefix + text + m.suffix
+}
@@ -0,0 +1,211 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const timingArrow = "-->"
+
+var positionRe = regexp.MustCompile(`(?i)^X1:(-?\d+)\s+X2:(-?\d+)\s+Y1:(-?\d+)\s+Y2:(-?\d+)$`)
+
+type srtTiming struct {
+	start, end time.Duration
+	position   *Position
+}
+
+// parseSRTTiming 解析 "00:00:01,000 --> 00:00:02,000 X1:.. X2:.. Y1:.. Y2:.."
+func parseSRTTiming(line string) (srtTiming, bool) {
+	var t srtTiming
+	left, right, ok := strings.Cut(line, timingArrow)
+	if !ok {
+		return t, false
+	}
+	start, err := parseTimestamp(strings.TrimSpace(left))
+	if err != nil {
+		return t, false
+	}
+	right = strings.TrimSpace(right)
+	endStr, rest, _ := strings.Cut(right, " ")
+	end, err := parseTimestamp(endStr)
+	if err != nil {
+		return t, false
+	}
+	t.start, t.end = start, end
+	if rest = strings.TrimSpace(rest); rest != "" {
+		m := positionRe.FindStringSubmatch(rest)
+		if m == nil {
+			return t, false
+		}
+		p := &Position{}
+		p.X1, _ = strconv.Atoi(m[1])
+		p.X2, _ = strconv.Atoi(m[2])
+		p.Y1, _ = strconv.Atoi(m[3])
+		p.Y2, _ = strconv.Atoi(m[4])
+		t.position = p
+	}
+	return t, true
+}
+
+func parseIndex(line string) (int, bool) {
+	if line == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(line)
+	return n, err == nil
+}
+
+// parseTimestamp 解析 hh:mm:ss,mmm，兼容 "." 分隔符、省略小时和不足三位的毫秒
+func parseTimestamp(s string) (time.Duration, error) {
+	main, frac, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	parts := strings.Split(main, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", s)
+	}
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp: %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	d *= time.Second
+	if frac != "" {
+		if len(frac) > 3 {
+			frac = frac[:3]
+		}
+		n, err := strconv.Atoi(frac)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp: %q", s)
+		}
+		for i := len(frac); i < 3; i++ {
+			n *= 10
+		}
+		d += time.Duration(n) * time.Millisecond
+	}
+	return d, nil
+}
+
+// formatTimestamp 以 hh:mm:ss<sep>mmm 输出时间
+func formatTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// srtCueStart 判断第 i 行是否为一条字幕的开始，返回时间轴所在行
+func srtCueStart(lines []string, i int) (timingLine int, ok bool) {
+	line := strings.TrimSpace(lines[i])
+	if _, isIndex := parseIndex(line); isIndex && i+1 < len(lines) {
+		if _, isTiming := parseSRTTiming(strings.TrimSpace(lines[i+1])); isTiming {
+			return i + 1, true
+		}
+	}
+	if _, isTiming := parseSRTTiming(line); isTiming {
+		return i, true
+	}
+	return 0, false
+}
+
+func parseSRT(doc *Document, content string) error {
+	lines := splitLines(content)
+	var cur *Cue
+	var curLines []string
+	var raw strings.Builder
+	flush := func() {
+		if cur == nil {
+			doc.header = raw.String()
+		} else {
+			cur.Text = strings.Join(curLines, "\n")
+			cur.snapshot(raw.String())
+			doc.Cues = append(doc.Cues, cur)
+		}
+		raw.Reset()
+	}
+	blank := false
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			blank = true
+			raw.WriteString(lines[i])
+			continue
+		}
+		// 新字幕既可以在空行之后，也可以紧跟上一条字幕的文本（缺少空行）
+		if timingLine, ok := srtCueStart(lines, i); ok {
+			flush()
+			timing, _ := parseSRTTiming(strings.TrimSpace(lines[timingLine]))
+			cur = &Cue{Index: len(doc.Cues) + 1, Start: timing.start, End: timing.end, Position: timing.position}
+			if timingLine != i {
+				cur.Index, _ = parseIndex(line)
+			}
+			curLines = curLines[:0]
+			for ; i <= timingLine; i++ {
+				raw.WriteString(lines[i])
+			}
+			i--
+			blank = false
+			continue
+		}
+		raw.WriteString(lines[i])
+		if cur == nil {
+			continue
+		}
+		if blank && len(curLines) > 0 {
+			// 空行之后出现的非字幕内容仍归入当前字幕
+			curLines = append(curLines, "")
+			blank = false
+		}
+		curLines = append(curLines, strings.TrimRight(lines[i], " \t\r\n"))
+	}
+	flush()
+	if len(doc.Cues) == 0 && strings.TrimSpace(content) != "" {
+		return fmt.Errorf("no subtitle cue found")
+	}
+	return nil
+}
+
+func writeSRT(buf *bytes.Buffer, doc *Document) {
+	buf.WriteString(doc.header)
+	for i, c := range doc.Cues {
+		if c.unchanged() {
+			buf.WriteString(c.raw)
+			continue
+		}
+		nl := doc.newline
+		if i > 0 {
+			ensureBlankLine(buf, nl)
+		}
+		index := c.Index
+		if index == 0 {
+			index = i + 1
+		}
+		fmt.Fprintf(buf, "%d%s", index, nl)
+		fmt.Fprintf(buf, "%s %s %s", formatTimestamp(c.Start, ","), timingArrow, formatTimestamp(c.End, ","))
+		if p := c.Position; p != nil {
+			fmt.Fprintf(buf, " X1:%d X2:%d Y1:%d Y2:%d", p.X1, p.X2, p.Y1, p.Y2)
+		}
+		buf.WriteString(nl)
+		for _, l := range c.Lines() {
+			buf.WriteString(l + nl)
+		}
+		buf.WriteString(nl)
+	}
+}
+
+// ensureBlankLine 保证已输出内容以空行结尾，用于分隔相邻的块
+func ensureBlankLine(buf *bytes.Buffer, nl string) {
+	if buf.Len() == 0 || bytes.HasSuffix(buf.Bytes(), []byte(nl+nl)) {
+		return
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte(nl)) {
+		buf.WriteString(nl)
+	}
+	buf.WriteString(nl)
+}
@@ -0,0 +1,107 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSRT(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "single line",
+			content: "1\n00:00:01,000 --> 00:00:02,000\nこんにちは\n\n2\n00:00:03,000 --> 00:00:04,000\nさようなら\n",
+			want:    []string{"こんにちは", "さようなら"},
+		},
+		{
+			name:    "multi line",
+			content: "1\n00:00:01,000 --> 00:00:02,000\n一行目\n二行目\n\n2\n00:00:03,000 --> 00:00:04,000\n三行目\n",
+			want:    []string{"一行目\n二行目", "三行目"},
+		},
+		{
+			name:    "missing blank line",
+			content: "1\n00:00:01,000 --> 00:00:02,000\nA\n2\n00:00:03,000 --> 00:00:04,000\nB\n",
+			want:    []string{"A", "B"},
+		},
+		{
+			name:    "bom crlf trailing whitespace",
+			content: "\ufeff1 \r\n00:00:01,000 --> 00:00:02,000  \r\nA  \r\n \r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nB\r\n\r\n\r\n",
+			want:    []string{"A", "B"},
+		},
+		{
+			name:    "missing index",
+			content: "00:00:01,000 --> 00:00:02,000\nA\n\n00:00:03.5 --> 00:00:04,000\nB",
+			want:    []string{"A", "B"},
+		},
+		{
+			name:    "not srt",
+			content: "hello\nworld\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(tt.content), SRT)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := doc.Texts()
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Parse() texts = %q, want %q", got, tt.want)
+			}
+			var buf bytes.Buffer
+			if _, err := doc.WriteTo(&buf); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			if buf.String() != tt.content {
+				t.Errorf("WriteTo() = %q, want %q", buf.String(), tt.content)
+			}
+		})
+	}
+}
+
+func TestParseSRTTiming(t *testing.T) {
+	content := "7\n01:02:03,045 --> 01:02:04,500 X1:10 X2:20 Y1:30 Y2:40\nA\n"
+	doc, err := Parse(strings.NewReader(content), SRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := doc.Cues[0]
+	if c.Index != 7 {
+		t.Errorf("Index = %d, want 7", c.Index)
+	}
+	if want := time.Hour + 2*time.Minute + 3*time.Second + 45*time.Millisecond; c.Start != want {
+		t.Errorf("Start = %v, want %v", c.Start, want)
+	}
+	if c.Position == nil || *c.Position != (Position{X1: 10, X2: 20, Y1: 30, Y2: 40}) {
+		t.Errorf("Position = %+v", c.Position)
+	}
+}
+
+func TestWriteSRT(t *testing.T) {
+	content := "\ufeff1\r\n00:00:01,000 --> 00:00:02,000 X1:1 X2:2 Y1:3 Y2:4\r\nA\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nB\r\nC\r\n\r\n3\r\n00:00:05,000 --> 00:00:06,000\r\nD\r\n"
+	doc, err := Parse(strings.NewReader(content), SRT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetTexts([]string{"甲", "乙\n丙", "D"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "\ufeff1\r\n00:00:01,000 --> 00:00:02,000 X1:1 X2:2 Y1:3 Y2:4\r\n甲\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n乙\r\n丙\r\n\r\n3\r\n00:00:05,000 --> 00:00:06,000\r\nD\r\n"
+	if buf.String() != want {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,194 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const bom = "\ufeff"
+
+// Format 字幕文件格式
+type Format string
+
+const (
+	SRT Format = "srt"
+	VTT Format = "vtt"
+	ASS Format = "ass"
+	SSA Format = "ssa"
+)
+
+// Formats 支持的字幕格式
+var Formats = []Format{SRT, VTT, ASS, SSA}
+
+// DetectFormat 根据文件扩展名判断字幕格式
+func DetectFormat(path string) (Format, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	return ParseFormat(ext)
+}
+
+// ParseFormat 解析格式名称
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported subtitle format: %q", name)
+}
+
+// Position SRT 扩展的显示坐标 (X1:100 X2:200 Y1:10 Y2:50)
+type Position struct {
+	X1, X2, Y1, Y2 int
+}
+
+// Cue 一条字幕
+type Cue struct {
+	Index    int
+	ID       string // WebVTT cue identifier
+	Start    time.Duration
+	End      time.Duration
+	Text     string // 多行文本以 "\n" 连接，不含首尾的格式标签
+	Position *Position
+	Settings string // WebVTT cue settings，如 "align:start position:10%"
+	Style    string // ASS/SSA 样式名
+
+	raw    string // 解析时的原始内容（含结尾空行），内容未修改时原样写回
+	post   string // 紧随其后的非字幕内容，如 WebVTT 的 NOTE 块、ASS 的 Comment 行
+	markup markup
+	fields []string // ASS/SSA Dialogue 行的原始字段
+	orig   cueState
+
+	secondary *Cue // 双语 ASS 字幕中第二语言的 Dialogue 行
+}
+
+type cueState struct {
+	index    int
+	id       string
+	start    time.Duration
+	end      time.Duration
+	text     string
+	settings string
+	style    string
+	position Position
+	hasPos   bool
+}
+
+func (c *Cue) state() cueState {
+	s := cueState{index: c.Index, id: c.ID, start: c.Start, end: c.End, text: c.Text, settings: c.Settings, style: c.Style}
+	if c.Position != nil {
+		s.position, s.hasPos = *c.Position, true
+	}
+	return s
+}
+
+// unchanged 字幕自解析后未被修改，可以原样写回
+func (c *Cue) unchanged() bool {
+	return c.raw != "" && c.state() == c.orig
+}
+
+func (c *Cue) snapshot(raw string) {
+	c.raw = raw
+	c.orig = c.state()
+}
+
+// Lines 返回字幕的各行文本
+func (c *Cue) Lines() []string {
+	return strings.Split(c.Text, "\n")
+}
+
+// Document 解析后的字幕文件
+type Document struct {
+	Format Format
+	Cues   []*Cue
+
+	bom     bool
+	newline string
+	header  string // 第一条字幕之前的原始内容
+	events  assEvents
+}
+
+// Texts 返回每条字幕的待翻译文本
+func (d *Document) Texts() []string {
+	texts := make([]string, len(d.Cues))
+	for i, c := range d.Cues {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+// SetTexts 按顺序替换每条字幕的文本，ASS 绘图行保持不变
+func (d *Document) SetTexts(texts []string) error {
+	if len(texts) != len(d.Cues) {
+		return fmt.Errorf("text count %d does not match cue count %d", len(texts), len(d.Cues))
+	}
+	for i, c := range d.Cues {
+		if c.markup.drawing {
+			continue
+		}
+		c.Text = texts[i]
+	}
+	return nil
+}
+
+// Parse 按指定格式解析字幕
+func Parse(r io.Reader, format Format) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	doc := &Document{Format: format, newline: "\n"}
+	if strings.HasPrefix(content, bom) {
+		doc.bom = true
+		content = strings.TrimPrefix(content, bom)
+	}
+	if strings.Contains(content, "\r\n") {
+		doc.newline = "\r\n"
+	}
+	switch format {
+	case SRT:
+		err = parseSRT(doc, content)
+	case VTT:
+		err = parseVTT(doc, content)
+	case ASS, SSA:
+		err = parseASS(doc, content)
+	default:
+		err = fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// WriteTo 按文档格式输出字幕，未修改的部分保持原样
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if d.bom {
+		buf.WriteString(bom)
+	}
+	switch d.Format {
+	case SRT:
+		writeSRT(&buf, d)
+	case VTT:
+		writeVTT(&buf, d)
+	case ASS, SSA:
+		writeASS(&buf, d)
+	default:
+		return 0, fmt.Errorf("unsupported subtitle format: %s", d.Format)
+	}
+	return buf.WriteTo(w)
+}
+
+// splitLines 按行拆分并保留行尾换行符
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,178 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const vttSignature = "WEBVTT"
+
+// vttTagRe 匹配 WebVTT 的行内标签，如 <v Speaker>、<c.class>、</c>、<00:00:01.000>
+var vttTagRe = regexp.MustCompile(`<[^<>\n]*>`)
+
+type vttTiming struct {
+	start, end time.Duration
+	settings   string
+}
+
+// parseVTTTiming 解析 "00:01.000 --> 00:02.000 align:start position:10%"
+func parseVTTTiming(line string) (vttTiming, bool) {
+	var t vttTiming
+	left, right, ok := strings.Cut(line, timingArrow)
+	if !ok {
+		return t, false
+	}
+	start, err := parseTimestamp(strings.TrimSpace(left))
+	if err != nil {
+		return t, false
+	}
+	fields := strings.Fields(right)
+	if len(fields) == 0 {
+		return t, false
+	}
+	end, err := parseTimestamp(fields[0])
+	if err != nil {
+		return t, false
+	}
+	t.start, t.end, t.settings = start, end, strings.Join(fields[1:], " ")
+	return t, true
+}
+
+// vttBlock 以空行分隔的块，raw 包含块之后的空行
+type vttBlock struct {
+	lines []string
+	raw   string
+}
+
+func splitVTTBlocks(lines []string) (leading string, blocks []vttBlock) {
+	blank := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			if len(blocks) == 0 {
+				leading += line
+			} else {
+				blocks[len(blocks)-1].raw += line
+			}
+			continue
+		}
+		if blank {
+			blocks = append(blocks, vttBlock{})
+			blank = false
+		}
+		cur := &blocks[len(blocks)-1]
+		cur.lines = append(cur.lines, line)
+		cur.raw += line
+	}
+	return leading, blocks
+}
+
+func parseVTT(doc *Document, content string) error {
+	if !strings.HasPrefix(strings.TrimLeft(content, " \t\r\n"), vttSignature) {
+		return fmt.Errorf("missing %s signature", vttSignature)
+	}
+	leading, blocks := splitVTTBlocks(splitLines(content))
+	doc.header = leading
+	var prev *Cue
+	for _, b := range blocks {
+		cues := parseVTTBlock(b)
+		if len(cues) == 0 {
+			// 头部、NOTE、STYLE、REGION 等块原样保留
+			if prev == nil {
+				doc.header += b.raw
+			} else {
+				prev.post += b.raw
+			}
+			continue
+		}
+		for _, c := range cues {
+			c.Index = len(doc.Cues) + 1
+			c.snapshot(c.raw)
+			doc.Cues = append(doc.Cues, c)
+			prev = c
+		}
+	}
+	return nil
+}
+
+// parseVTTBlock 解析字幕块，缺少空行分隔时一个块中可能包含多条字幕
+func parseVTTBlock(b vttBlock) []*Cue {
+	first := strings.TrimSpace(b.lines[0])
+	if first == vttSignature || strings.HasPrefix(first, vttSignature+" ") || strings.HasPrefix(first, vttSignature+"\t") ||
+		strings.HasPrefix(first, "NOTE") || first == "STYLE" || first == "REGION" {
+		return nil
+	}
+	var cues []*Cue
+	var cur *Cue
+	var text []string
+	var raw string
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		m, plain := splitMarkup(strings.Join(text, "\n"), vttTagRe)
+		cur.Text, cur.markup = plain, m
+		cur.snapshot(raw)
+		cues = append(cues, cur)
+	}
+	for i := 0; i < len(b.lines); i++ {
+		line := strings.TrimRight(b.lines[i], " \t\r\n")
+		id := ""
+		timingLine := i
+		if !strings.Contains(line, timingArrow) && i+1 < len(b.lines) && cur == nil {
+			id, timingLine = line, i+1
+		}
+		if timing, ok := parseVTTTiming(strings.TrimSpace(b.lines[timingLine])); ok {
+			flush()
+			cur = &Cue{ID: id, Start: timing.start, End: timing.end, Settings: timing.settings}
+			text, raw = nil, strings.Join(b.lines[i:timingLine+1], "")
+			i = timingLine
+			continue
+		}
+		if cur == nil {
+			return nil
+		}
+		text = append(text, line)
+		raw += b.lines[i]
+	}
+	if cur == nil {
+		return nil
+	}
+	// 块之后的空行归入最后一条字幕
+	raw += strings.TrimPrefix(b.raw, strings.Join(b.lines, ""))
+	flush()
+	return cues
+}
+
+func writeVTT(buf *bytes.Buffer, doc *Document) {
+	if doc.header == "" {
+		buf.WriteString(vttSignature + doc.newline + doc.newline)
+	} else {
+		buf.WriteString(doc.header)
+	}
+	for _, c := range doc.Cues {
+		if c.unchanged() {
+			buf.WriteString(c.raw)
+			buf.WriteString(c.post)
+			continue
+		}
+		nl := doc.newline
+		ensureBlankLine(buf, nl)
+		if c.ID != "" {
+			buf.WriteString(c.ID + nl)
+		}
+		fmt.Fprintf(buf, "%s %s %s", formatTimestamp(c.Start, "."), timingArrow, formatTimestamp(c.End, "."))
+		if c.Settings != "" {
+			buf.WriteString(" " + c.Settings)
+		}
+		buf.WriteString(nl)
+		for _, l := range strings.Split(c.markup.apply(c.Text), "\n") {
+			buf.WriteString(l + nl)
+		}
+		buf.WriteString(nl)
+		buf.WriteString(c.post)
+	}
+}
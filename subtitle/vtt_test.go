@@ -0,0 +1,99 @@
+package subtitle
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const vttSample = `WEBVTT - sample
+Kind: captions
+
+STYLE
+::cue(.yellow) { color: yellow; }
+
+REGION
+id:top width:40%
+
+NOTE first note
+
+intro
+00:01.000 --> 00:02.500 align:start position:10%
+<v Roger>Hello there.
+
+00:00:03.000 --> 00:00:04.000 line:0
+<c.yellow>Two</c>
+lines</c>
+
+NOTE
+a note between cues
+
+00:05.000 --> 00:06.000
+plain
+`
+
+func TestParseVTT(t *testing.T) {
+	doc, err := Parse(strings.NewReader(vttSample), VTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Hello there.", "Two</c>\nlines"}
+	if len(doc.Cues) != 3 {
+		t.Fatalf("cue count = %d, want 3", len(doc.Cues))
+	}
+	for i, w := range want {
+		if doc.Cues[i].Text != w {
+			t.Errorf("cue %d text = %q, want %q", i, doc.Cues[i].Text, w)
+		}
+	}
+	if doc.Cues[0].ID != "intro" || doc.Cues[0].Settings != "align:start position:10%" {
+		t.Errorf("cue 0 = %+v", doc.Cues[0])
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != vttSample {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), vttSample)
+	}
+}
+
+func TestWriteVTT(t *testing.T) {
+	doc, err := Parse(strings.NewReader(vttSample), VTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetTexts([]string{"你好。", "两\n行", "plain"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"intro\n00:01.000 --> 00:02.500 align:start position:10%\n<v Roger>Hello there.",
+		"intro\n00:00:01.000 --> 00:00:02.500 align:start position:10%\n<v Roger>你好。",
+		"<c.yellow>Two</c>\nlines</c>", "<c.yellow>两\n行</c>",
+	).Replace(vttSample)
+	if buf.String() != want {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr bool
+	}{
+		{path: "a.srt", want: SRT},
+		{path: "dir/a.en.VTT", want: VTT},
+		{path: "a.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := DetectFormat(tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DetectFormat(%q) = %v, %v", tt.path, got, err)
+		}
+	}
+}